@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+)
+
+// timedCall runs an OCI SDK call and logs its duration at debug level. The retry policy that
+// applies to the call, the one in metadata or else the client's, is wrapped so the number of
+// attempts can be reported; more than one attempt usually means the service throttled or failed
+// transiently. Without either policy metadata is left alone and the attempts are unknown.
+func timedCall[T any](operation string, client any, metadata *common.RequestMetadata, call func() (T, error)) (T, error) {
+	retryPolicy := metadata.RetryPolicy
+	if retryPolicy == nil {
+		retryPolicy = clientRetryPolicy(client)
+	}
+	var attempts atomic.Uint32
+	if retryPolicy != nil {
+		policy := *retryPolicy
+		shouldRetry := policy.ShouldRetryOperation
+		policy.ShouldRetryOperation = func(response common.OCIOperationResponse) bool {
+			attempts.Store(uint32(response.AttemptNumber))
+			return shouldRetry(response)
+		}
+		metadata.RetryPolicy = &policy
+	}
+
+	start := time.Now()
+	result, err := call()
+	attrs := []any{"operation", operation, "duration", time.Since(start).Round(time.Millisecond)}
+	if retryPolicy != nil {
+		attrs = append(attrs, "attempts", attempts.Load(), "retried", attempts.Load() > 1)
+	} else {
+		attrs = append(attrs, "attempts", "unknown")
+	}
+	var serviceErr common.ServiceError
+	if errors.As(err, &serviceErr) {
+		attrs = append(attrs, "status", serviceErr.GetHTTPStatusCode())
+	}
+	slog.Debug("OCI API call", attrs...)
+	return result, err
+}
+
+// clientRetryPolicy returns the retry policy configured on an SDK client, which includes the one
+// from OCI_SDK_DEFAULT_RETRY_ENABLED or common.GlobalRetry, or nil if there is none or client is
+// not an SDK client.
+func clientRetryPolicy(client any) *common.RetryPolicy {
+	switch client := client.(type) {
+	case core.ComputeClient:
+		return client.Configuration.RetryPolicy
+	case identity.IdentityClient:
+		return client.Configuration.RetryPolicy
+	}
+	return nil
+}
+
+// getInstance is a timed ComputeClient.GetInstance.
+func getInstance(client computeAPI, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
+	return timedCall("GetInstance", client, &request.RequestMetadata, func() (core.GetInstanceResponse, error) {
+		return apiCall(client.GetInstance, request)
+	})
+}
+
+// listInstances is a timed ComputeClient.ListInstances.
+func listInstances(client computeAPI, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+	return timedCall("ListInstances", client, &request.RequestMetadata, func() (core.ListInstancesResponse, error) {
+		return apiCall(client.ListInstances, request)
+	})
+}
+
+// listImages is a timed ComputeClient.ListImages.
+func listImages(client computeAPI, request core.ListImagesRequest) (core.ListImagesResponse, error) {
+	return timedCall("ListImages", client, &request.RequestMetadata, func() (core.ListImagesResponse, error) {
+		return apiCall(client.ListImages, request)
+	})
+}
+
+// listShapes is a timed ComputeClient.ListShapes.
+func listShapes(client computeAPI, request core.ListShapesRequest) (core.ListShapesResponse, error) {
+	return timedCall("ListShapes", client, &request.RequestMetadata, func() (core.ListShapesResponse, error) {
+		return apiCall(client.ListShapes, request)
+	})
+}
+
+// listCompartments is a timed IdentityClient.ListCompartments.
+func listCompartments(client compartmentLister, request identity.ListCompartmentsRequest) (identity.ListCompartmentsResponse, error) {
+	return timedCall("ListCompartments", client, &request.RequestMetadata, func() (identity.ListCompartmentsResponse, error) {
+		return apiCall(client.ListCompartments, request)
+	})
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func TestTimedCallKeepsRetryPolicy(t *testing.T) {
+	defaultPolicy := common.DefaultRetryPolicy()
+	withPolicy := core.ComputeClient{}
+	withPolicy.Configuration.RetryPolicy = &defaultPolicy
+
+	tests := []struct {
+		name         string
+		client       any
+		wantAttempts uint // 0 means no policy is set on the request
+	}{
+		{name: "client without a policy", client: core.ComputeClient{}},
+		{name: "fake client", client: &fakeCompute{}},
+		{name: "client with the default policy", client: withPolicy, wantAttempts: defaultPolicy.MaximumNumberAttempts},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var metadata common.RequestMetadata
+			_, _ = timedCall("Test", test.client, &metadata, func() (struct{}, error) { return struct{}{}, nil })
+			switch {
+			case test.wantAttempts == 0 && metadata.RetryPolicy != nil:
+				t.Errorf("timedCall() set a retry policy on a request whose client has none")
+			case test.wantAttempts != 0 && metadata.RetryPolicy == nil:
+				t.Errorf("timedCall() left the request without the client's retry policy")
+			case test.wantAttempts != 0 && metadata.RetryPolicy.MaximumNumberAttempts != test.wantAttempts:
+				t.Errorf("timedCall() retry attempts = %d, want %d", metadata.RetryPolicy.MaximumNumberAttempts, test.wantAttempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultAPITimeout bounds a single OCI API call unless --timeout overrides it.
+const defaultAPITimeout = 60 * time.Second
+
+// cancelledByUserMessage is printed instead of an error when a signal cancelled the command.
+const cancelledByUserMessage = "Cancelled by user."
+
+// interruptGracePeriod is how long the command gets to wind down after Ctrl-C before the
+// process exits anyway, e.g. when it is blocked on a prompt rather than an API call.
+const interruptGracePeriod = 3 * time.Second
+
+// apiTimeout is the per-call deadline set from --timeout; 0 disables it.
+var apiTimeout = defaultAPITimeout
+
+// rootContext is cancelled when the command is interrupted with Ctrl-C or SIGTERM; commands
+// reach it through cmd.Context() or apiContext.
+var rootContext = context.Background()
+
+// applyTimeoutFlag reads the persistent --timeout flag.
+func applyTimeoutFlag(cmd *cobra.Command) error {
+	timeoutFlag, _ := cmd.Flags().GetDuration("timeout")
+	if timeoutFlag < 0 {
+		return fmt.Errorf("invalid --timeout %s", timeoutFlag)
+	}
+	apiTimeout = timeoutFlag
+	return nil
+}
+
+// cancelOnInterrupt makes the first Ctrl-C or SIGTERM cancel rootContext, so in-flight API calls
+// and wait loops stop, and exits the process if the command has not finished within the grace
+// period. Once the signal is received the default handlers are restored, so a second Ctrl-C
+// exits immediately.
+func cancelOnInterrupt() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	rootContext = ctx
+
+	go func() {
+		<-ctx.Done()
+		stop()
+		time.Sleep(interruptGracePeriod)
+		fmt.Fprintln(os.Stderr, cancelledByUserMessage)
+		os.Exit(130)
+	}()
+}
+
+// apiContext returns the context for a single OCI API call, cancelled on Ctrl-C and expiring
+// after --timeout. Callers must call the returned cancel func once the call has returned.
+func apiContext() (context.Context, context.CancelFunc) {
+	if apiTimeout <= 0 {
+		return context.WithCancel(rootContext)
+	}
+	return context.WithTimeout(rootContext, apiTimeout)
+}
+
+// apiCall makes one OCI API call, such as apiCall(client.GetInstance, request), with a context
+// from apiContext that is released as soon as the call returns.
+func apiCall[Request, Response any](call func(context.Context, Request) (Response, error), request Request) (Response, error) {
+	ctx, cancel := apiContext()
+	defer cancel()
+	return call(ctx, request)
+}
+
+// pause sleeps for d, returning early when the command is interrupted. Wait loops use it
+// between polls; their next API call then fails with context.Canceled.
+func pause(d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-rootContext.Done():
+	}
+}
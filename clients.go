@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+)
+
+// computeAPI is the part of core.ComputeClient that command logic depends on, so tests can
+// substitute a fake.
+type computeAPI interface {
+	GetInstance(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error)
+	ListInstances(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error)
+	LaunchInstance(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error)
+	InstanceAction(ctx context.Context, request core.InstanceActionRequest) (core.InstanceActionResponse, error)
+	TerminateInstance(ctx context.Context, request core.TerminateInstanceRequest) (core.TerminateInstanceResponse, error)
+	GetImage(ctx context.Context, request core.GetImageRequest) (core.GetImageResponse, error)
+	ListImages(ctx context.Context, request core.ListImagesRequest) (core.ListImagesResponse, error)
+	ListShapes(ctx context.Context, request core.ListShapesRequest) (core.ListShapesResponse, error)
+}
+
+// identityAPI is the part of identity.IdentityClient that command logic depends on, so tests can
+// substitute a fake.
+type identityAPI interface {
+	compartmentLister
+	GetCompartment(ctx context.Context, request identity.GetCompartmentRequest) (identity.GetCompartmentResponse, error)
+}
+
+var (
+	_ computeAPI  = core.ComputeClient{}
+	_ identityAPI = identity.IdentityClient{}
+)
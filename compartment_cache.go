@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/spf13/cobra"
+)
+
+// defaultCompartmentCacheTTL is how long cached compartment listings are used by default.
+const defaultCompartmentCacheTTL = 15 * time.Minute
+
+// compartmentCacheTTL is the maximum age of a usable cache entry; 0 disables the cache.
+// It is set from --cache-ttl and --no-cache by applyCacheFlags.
+var compartmentCacheTTL = defaultCompartmentCacheTTL
+
+// cacheDisabled is set by --no-cache, which bypasses both the compartment cache and the image
+// name cache used by completion.
+var cacheDisabled bool
+
+// errCompartmentNotFound marks compartment lookups that found no match, which invalidates a cached listing.
+var errCompartmentNotFound = errors.New("compartment not found")
+
+// compartmentCacheEntry is the cached compartment subtree of one tenancy.
+type compartmentCacheEntry struct {
+	FetchedAt    time.Time              `json:"fetchedAt"`
+	Compartments []identity.Compartment `json:"compartments"`
+}
+
+// applyCacheFlags reads the persistent --cache-ttl and --no-cache flags.
+func applyCacheFlags(cmd *cobra.Command) {
+	noCacheFlag, _ := cmd.Flags().GetBool("no-cache")
+	cacheTTLFlag, _ := cmd.Flags().GetDuration("cache-ttl")
+	compartmentCacheTTL = cacheTTLFlag
+	cacheDisabled = noCacheFlag
+	if noCacheFlag {
+		compartmentCacheTTL = 0
+	}
+}
+
+// compartmentCacheFilePath returns the location of the compartment cache file.
+func compartmentCacheFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".oci-cli-cache", "compartments.json"), nil
+}
+
+// loadCompartmentCache reads the cache file. A missing or corrupt file yields an empty cache.
+func loadCompartmentCache() map[string]compartmentCacheEntry {
+	cache := map[string]compartmentCacheEntry{}
+	path, err := compartmentCacheFilePath()
+	if err != nil {
+		return cache
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil || cache == nil {
+		return map[string]compartmentCacheEntry{}
+	}
+	return cache
+}
+
+// saveCompartmentCache writes the cache file, creating its directory if needed.
+func saveCompartmentCache(cache map[string]compartmentCacheEntry) error {
+	path, err := compartmentCacheFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cache file '%s': %w", path, err)
+	}
+	return nil
+}
+
+// cachedSubtreeCompartments returns the compartment subtree of a tenancy, served from the
+// on-disk cache when an entry younger than the cache TTL exists and refresh is false. It
+// reports whether the result came from the cache. Failing to write the cache is not an error.
+func cachedSubtreeCompartments(client compartmentLister, tenancyOCID string, refresh bool) ([]identity.Compartment, bool, error) {
+	if compartmentCacheTTL <= 0 {
+		compartments, err := listSubtreeCompartments(client, tenancyOCID)
+		return compartments, false, err
+	}
+
+	cache := loadCompartmentCache()
+	if entry, ok := cache[tenancyOCID]; ok && !refresh && time.Since(entry.FetchedAt) < compartmentCacheTTL {
+		return entry.Compartments, true, nil
+	}
+
+	compartments, err := listSubtreeCompartments(client, tenancyOCID)
+	if err != nil {
+		return nil, false, err
+	}
+	cache[tenancyOCID] = compartmentCacheEntry{FetchedAt: time.Now(), Compartments: compartments}
+	if err := saveCompartmentCache(cache); err != nil {
+		slog.Warn("failed to save compartment cache", "error", err)
+	}
+	return compartments, false, nil
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/spf13/cobra"
+)
+
+// compartmentJSON is the serializable projection of a compartment. Children is only
+// populated for the nested --tree representation.
+type compartmentJSON struct {
+	ID             string             `json:"id"`
+	Name           string             `json:"name"`
+	Description    string             `json:"description"`
+	CompartmentID  string             `json:"compartmentId"`
+	LifecycleState string             `json:"lifecycleState"`
+	Children       []*compartmentJSON `json:"children,omitempty"`
+}
+
+func newCompartmentJSON(compartment identity.Compartment) *compartmentJSON {
+	return &compartmentJSON{
+		ID:             stringValue(compartment.Id),
+		Name:           stringValue(compartment.Name),
+		Description:    stringValue(compartment.Description),
+		CompartmentID:  stringValue(compartment.CompartmentId),
+		LifecycleState: string(compartment.LifecycleState),
+	}
+}
+
+// compartmentLister is the part of identity.IdentityClient used to walk compartments.
+type compartmentLister interface {
+	ListCompartments(ctx context.Context, request identity.ListCompartmentsRequest) (identity.ListCompartmentsResponse, error)
+}
+
+// listCompartmentsRecursive adds a row for every compartment below request.CompartmentId to table,
+// each followed by its own descendants. Every compartment's children are fully paginated with a
+// request of their own before the walk moves on, so page tokens never leak between levels.
+func listCompartmentsRecursive(client compartmentLister, request *identity.ListCompartmentsRequest, depth int, table *textTable) error {
+	levelRequest := *request
+	compartments, _, err := collectPages(0, func(page *string) ([]identity.Compartment, *string, error) {
+		levelRequest.Page = page
+		response, err := listCompartments(client, levelRequest)
+		return response.Items, response.OpcNextPage, err
+	})
+	if err != nil {
+		return err
+	}
+
+	indent := strings.Repeat("  ", depth)
+	for _, compartment := range compartments {
+		table.addRow(indent+stringValue(compartment.Name), stringValue(compartment.Id), string(compartment.LifecycleState), stringValue(compartment.Description))
+		if compartment.Id == nil {
+			continue
+		}
+		subRequest := *request
+		subRequest.CompartmentId = compartment.Id
+		subRequest.Page = nil
+		if err := listCompartmentsRecursive(client, &subRequest, depth+1, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listSubtreeCompartments returns every compartment below rootID using a single
+// CompartmentIdInSubtree query, following pagination.
+func listSubtreeCompartments(client compartmentLister, rootID string) ([]identity.Compartment, error) {
+	var compartments []identity.Compartment
+	request := identity.ListCompartmentsRequest{
+		CompartmentId:          &rootID,
+		CompartmentIdInSubtree: common.Bool(true),
+		AccessLevel:            identity.ListCompartmentsAccessLevelAny,
+	}
+	for {
+		response, err := listCompartments(client, request)
+		if err != nil {
+			return nil, err
+		}
+		compartments = append(compartments, response.Items...)
+		if response.OpcNextPage == nil {
+			return compartments, nil
+		}
+		request.Page = response.OpcNextPage
+	}
+}
+
+// filterCompartmentsByState keeps the compartments in the given state; an empty state keeps all.
+func filterCompartmentsByState(compartments []identity.Compartment, state identity.CompartmentLifecycleStateEnum) []identity.Compartment {
+	if state == "" {
+		return compartments
+	}
+	var matched []identity.Compartment
+	for _, compartment := range compartments {
+		if compartment.LifecycleState == state {
+			matched = append(matched, compartment)
+		}
+	}
+	return matched
+}
+
+// buildCompartmentTree nests a flat compartment list under their parents and returns
+// the direct children of rootID. Siblings are sorted by name.
+func buildCompartmentTree(compartments []identity.Compartment, rootID string) []*compartmentJSON {
+	children := map[string][]*compartmentJSON{}
+	for _, compartment := range compartments {
+		node := newCompartmentJSON(compartment)
+		children[node.CompartmentID] = append(children[node.CompartmentID], node)
+	}
+
+	var attach func(parentID string) []*compartmentJSON
+	attach = func(parentID string) []*compartmentJSON {
+		nodes := children[parentID]
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+		for _, node := range nodes {
+			node.Children = attach(node.ID)
+		}
+		return nodes
+	}
+	return attach(rootID)
+}
+
+// descendantCompartmentIDs returns the IDs of all compartments below rootID in a flat subtree listing.
+func descendantCompartmentIDs(compartments []identity.Compartment, rootID string) []string {
+	var ids []string
+	for _, node := range buildCompartmentTree(compartments, rootID) {
+		var walk func(node *compartmentJSON)
+		walk = func(node *compartmentJSON) {
+			if node.LifecycleState == string(identity.CompartmentLifecycleStateActive) {
+				ids = append(ids, node.ID)
+			}
+			for _, child := range node.Children {
+				walk(child)
+			}
+		}
+		walk(node)
+	}
+	return ids
+}
+
+// compartmentPaths maps every compartment of a flat subtree listing to its slash-delimited
+// path from the tenancy root, e.g. "root/team/prod".
+func compartmentPaths(compartments []identity.Compartment, tenancyOCID string) map[string]string {
+	byID := map[string]identity.Compartment{}
+	for _, compartment := range compartments {
+		byID[*compartment.Id] = compartment
+	}
+
+	paths := map[string]string{tenancyOCID: "root"}
+	var pathOf func(id string) string
+	pathOf = func(id string) string {
+		if path, ok := paths[id]; ok {
+			return path
+		}
+		compartment, ok := byID[id]
+		if !ok {
+			return id
+		}
+		path := pathOf(stringValue(compartment.CompartmentId)) + "/" + stringValue(compartment.Name)
+		paths[id] = path
+		return path
+	}
+	for id := range byID {
+		pathOf(id)
+	}
+	return paths
+}
+
+func newCreateCompartmentCmd() *cobra.Command {
+	var createCompartmentCmd = &cobra.Command{
+		Use:   "create",
+		Short: "Create a compartment",
+		Long: `Creates a compartment under --parent-id (the tenancy root by default). Compartments are
+managed in the tenancy's home region, so run this with a home-region profile.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parentInput, _ := cmd.Flags().GetString("parent-id")
+			nameFlag, _ := cmd.Flags().GetString("name")
+			descriptionFlag, _ := cmd.Flags().GetString("description")
+			waitFlag, _ := cmd.Flags().GetBool("wait")
+			waitTimeoutFlag, _ := cmd.Flags().GetInt("wait-timeout")
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			var parentID string
+			if parentInput == "" {
+				parentID, err = configProvider.TenancyOCID()
+				if err != nil {
+					return fmt.Errorf("getting tenancy OCID: %w", err)
+				}
+			} else {
+				parentID, err = resolveCompartmentID(parentInput, configProvider)
+				if err != nil {
+					return fmt.Errorf("resolving parent compartment: %w", err)
+				}
+			}
+
+			// The service requires a description
+			description := descriptionFlag
+			if description == "" {
+				description = nameFlag
+			}
+
+			identityClient, err := identity.NewIdentityClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating identity client: %w", err)
+			}
+
+			response, err := apiCall(identityClient.CreateCompartment, identity.CreateCompartmentRequest{
+				CreateCompartmentDetails: identity.CreateCompartmentDetails{
+					CompartmentId: &parentID,
+					Name:          &nameFlag,
+					Description:   &description,
+				},
+				OpcRetryToken: common.String(retryToken(cmd)),
+			})
+			if err != nil {
+				return fmt.Errorf("creating compartment: %w", err)
+			}
+			compartmentID := stringValue(response.Compartment.Id)
+			fmt.Printf("Compartment creation initiated.\nCompartment ID: %s\nName: %s\nState: %s\n", compartmentID, nameFlag, response.Compartment.LifecycleState)
+
+			if !waitFlag {
+				return nil
+			}
+			fmt.Println("Waiting for compartment to become ACTIVE...")
+			compartment, err := waitForCompartmentActive(identityClient, compartmentID, time.Duration(waitTimeoutFlag)*time.Second)
+			if err != nil {
+				return fmt.Errorf("compartment did not become ACTIVE: %w", err)
+			}
+			fmt.Printf("Compartment is %s.\n", compartment.LifecycleState)
+			return nil
+		},
+	}
+
+	createCompartmentCmd.Flags().String("parent-id", "", "(Optional) OCID, name or path of the parent compartment (defaults to tenancy root)")
+	createCompartmentCmd.Flags().String("name", "", "Name of the new compartment, unique within the tenancy (Required)")
+	createCompartmentCmd.Flags().String("description", "", "(Optional) Description of the new compartment (defaults to its name)")
+	createCompartmentCmd.Flags().Bool("wait", false, "(Optional) Wait until the compartment is ACTIVE before exiting")
+	createCompartmentCmd.Flags().Int("wait-timeout", 300, "(Optional) Maximum number of seconds to wait with --wait")
+	addRetryTokenFlag(createCompartmentCmd)
+	_ = createCompartmentCmd.MarkFlagRequired("name")
+
+	return createCompartmentCmd
+}
+
+// waitForCompartmentActive polls a compartment until it is ACTIVE, is being deleted, or timeout elapses.
+func waitForCompartmentActive(client identity.IdentityClient, compartmentID string, timeout time.Duration) (identity.Compartment, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		response, err := apiCall(client.GetCompartment, identity.GetCompartmentRequest{CompartmentId: &compartmentID})
+		if err != nil {
+			return identity.Compartment{}, fmt.Errorf("failed to get compartment: %w", err)
+		}
+		compartment := response.Compartment
+		switch compartment.LifecycleState {
+		case identity.CompartmentLifecycleStateActive:
+			return compartment, nil
+		case identity.CompartmentLifecycleStateDeleting, identity.CompartmentLifecycleStateDeleted:
+			return compartment, fmt.Errorf("compartment entered state %s", compartment.LifecycleState)
+		}
+		if time.Now().After(deadline) {
+			return compartment, fmt.Errorf("timed out after %s (last state: %s)", timeout, compartment.LifecycleState)
+		}
+		pause(5 * time.Second)
+	}
+}
+
+func newDeleteCompartmentCmd() *cobra.Command {
+	var deleteCompartmentCmd = &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a compartment",
+		Long: `Deletes a compartment. Deletion is asynchronous and fails if the compartment still
+contains resources; with --wait the command follows the deletion work request and reports
+why it failed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			idFlag, _ := cmd.Flags().GetString("id")
+			nameFlag, _ := cmd.Flags().GetString("name")
+			confirmFlag, _ := cmd.Flags().GetBool("confirm")
+			waitFlag, _ := cmd.Flags().GetBool("wait")
+			waitTimeoutFlag, _ := cmd.Flags().GetInt("wait-timeout")
+
+			if idFlag != "" && nameFlag != "" {
+				return errors.New("specify either --id or --name, not both")
+			}
+			if idFlag == "" && nameFlag == "" {
+				return errors.New("specify --id or --name")
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			compartmentID := idFlag
+			if nameFlag != "" {
+				compartmentID, err = resolveCompartmentID(nameFlag, configProvider)
+				if err != nil {
+					return fmt.Errorf("resolving compartment: %w", err)
+				}
+			}
+
+			identityClient, err := identity.NewIdentityClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating identity client: %w", err)
+			}
+
+			compartmentResponse, err := apiCall(identityClient.GetCompartment, identity.GetCompartmentRequest{CompartmentId: &compartmentID})
+			if err != nil {
+				return fmt.Errorf("getting compartment: %w", err)
+			}
+			name := stringValue(compartmentResponse.Compartment.Name)
+
+			if !confirmFlag {
+				if !confirmPrompt(fmt.Sprintf("Delete compartment '%s' (%s)?", name, compartmentID)) {
+					return errors.New("aborted")
+				}
+			}
+
+			response, err := apiCall(identityClient.DeleteCompartment, identity.DeleteCompartmentRequest{CompartmentId: &compartmentID})
+			if err != nil {
+				return fmt.Errorf("deleting compartment: %w", err)
+			}
+			workRequestID := stringValue(response.OpcWorkRequestId)
+			fmt.Printf("Compartment deletion initiated.\nCompartment: %s (%s)\nWork Request ID: %s\n", name, compartmentID, workRequestID)
+
+			if !waitFlag {
+				return nil
+			}
+			fmt.Println("Waiting for the deletion to finish (this can take a while)...")
+			if err := waitForIamWorkRequest(identityClient, workRequestID, time.Duration(waitTimeoutFlag)*time.Second); err != nil {
+				return fmt.Errorf("compartment deletion did not succeed: %w", err)
+			}
+			fmt.Printf("Compartment '%s' deleted.\n", name)
+			return nil
+		},
+	}
+
+	deleteCompartmentCmd.Flags().String("id", "", "OCID of the compartment (Required unless --name is given)")
+	deleteCompartmentCmd.Flags().String("name", "", "Name or path of the compartment (Required unless --id is given)")
+	deleteCompartmentCmd.Flags().Bool("confirm", false, "(Optional) Skip the interactive confirmation prompt")
+	deleteCompartmentCmd.Flags().Bool("wait", false, "(Optional) Wait for the deletion work request to finish and report its outcome")
+	deleteCompartmentCmd.Flags().Int("wait-timeout", 1800, "(Optional) Maximum number of seconds to wait with --wait")
+
+	return deleteCompartmentCmd
+}
+
+// waitForIamWorkRequest polls an IAM work request until it succeeds, fails, or timeout elapses,
+// printing its progress like waitForWorkRequest. A failed work request is reported with the
+// messages of its errors.
+func waitForIamWorkRequest(client identity.IdentityClient, workRequestID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	lastProgress := ""
+	for {
+		response, err := apiCall(client.GetIamWorkRequest, identity.GetIamWorkRequestRequest{IamWorkRequestId: &workRequestID})
+		if err != nil {
+			return fmt.Errorf("failed to get work request: %w", err)
+		}
+		status := response.IamWorkRequest.Status
+		progress := fmt.Sprintf("%s (%.0f%% complete)", status, float32Value(response.IamWorkRequest.PercentComplete))
+		if progress != lastProgress {
+			fmt.Printf("Work request: %s\n", progress)
+			lastProgress = progress
+		}
+		switch status {
+		case identity.IamWorkRequestStatusSucceeded:
+			return nil
+		case identity.IamWorkRequestStatusFailed, identity.IamWorkRequestStatusCanceled:
+			errorsResponse, err := apiCall(client.ListIamWorkRequestErrors, identity.ListIamWorkRequestErrorsRequest{IamWorkRequestId: &workRequestID})
+			if err != nil || len(errorsResponse.Items) == 0 {
+				return fmt.Errorf("work request %s", status)
+			}
+			messages := make([]string, len(errorsResponse.Items))
+			for i, item := range errorsResponse.Items {
+				messages[i] = stringValue(item.Message)
+			}
+			return fmt.Errorf("work request %s: %s", status, strings.Join(messages, "; "))
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s (last status: %s)", timeout, status)
+		}
+		pause(10 * time.Second)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+)
+
+// fakeCompartmentLister serves the children of each compartment in fixed pages and fails on a
+// page token that was not issued for the compartment being listed.
+type fakeCompartmentLister struct {
+	pages map[string][][]string // parent ID -> pages of child IDs
+	calls []string
+}
+
+func (f *fakeCompartmentLister) ListCompartments(_ context.Context, request identity.ListCompartmentsRequest) (identity.ListCompartmentsResponse, error) {
+	parent := stringValue(request.CompartmentId)
+	f.calls = append(f.calls, parent+"@"+stringValue(request.Page))
+
+	index := 0
+	if request.Page != nil {
+		token := *request.Page
+		if !strings.HasPrefix(token, parent+"#") {
+			return identity.ListCompartmentsResponse{}, fmt.Errorf("page token %q used to list children of %q", token, parent)
+		}
+		fmt.Sscanf(strings.TrimPrefix(token, parent+"#"), "%d", &index)
+	}
+
+	var response identity.ListCompartmentsResponse
+	pages := f.pages[parent]
+	if index >= len(pages) {
+		return response, nil
+	}
+	for _, id := range pages[index] {
+		response.Items = append(response.Items, identity.Compartment{
+			Id:   common.String(id),
+			Name: common.String("name-" + id),
+		})
+	}
+	if index+1 < len(pages) {
+		response.OpcNextPage = common.String(fmt.Sprintf("%s#%d", parent, index+1))
+	}
+	return response, nil
+}
+
+func TestListCompartmentsRecursivePaginatesEachLevel(t *testing.T) {
+	lister := &fakeCompartmentLister{pages: map[string][][]string{
+		"root": {{"a", "b"}, {"c"}},
+		"a":    {{"a1"}, {"a2", "a3"}},
+		"c":    {{"c1"}, {"c2"}, {"c3"}},
+		"a2":   {{"a2x"}, {"a2y"}},
+	}}
+	table := &textTable{columns: []tableColumn{{header: "NAME"}, {header: "ID"}, {header: "STATE"}, {header: "DESCRIPTION"}}}
+
+	request := identity.ListCompartmentsRequest{
+		CompartmentId:  common.String("root"),
+		LifecycleState: identity.CompartmentLifecycleStateActive,
+	}
+	if err := listCompartmentsRecursive(lister, &request, 0, table); err != nil {
+		t.Fatalf("listCompartmentsRecursive: %v", err)
+	}
+
+	var got []string
+	for _, row := range table.rows {
+		got = append(got, row[0])
+	}
+	want := []string{
+		"name-a",
+		"  name-a1",
+		"  name-a2",
+		"    name-a2x",
+		"    name-a2y",
+		"  name-a3",
+		"name-b",
+		"name-c",
+		"  name-c1",
+		"  name-c2",
+		"  name-c3",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rows =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Each compartment is listed exactly once per page, starting from its first page
+	calls := map[string]int{}
+	for _, call := range lister.calls {
+		calls[call]++
+	}
+	for _, call := range []string{"root@", "root@root#1", "a@", "a@a#1", "a2@", "a2@a2#1", "c@", "c@c#1", "c@c#2", "b@"} {
+		if calls[call] != 1 {
+			t.Errorf("call %s made %d times, want 1 (calls: %v)", call, calls[call], lister.calls)
+		}
+	}
+}
+
+func TestListCompartmentsRecursiveKeepsRequestFilters(t *testing.T) {
+	var states []identity.CompartmentLifecycleStateEnum
+	lister := compartmentListerFunc(func(request identity.ListCompartmentsRequest) (identity.ListCompartmentsResponse, error) {
+		states = append(states, request.LifecycleState)
+		if stringValue(request.CompartmentId) == "root" {
+			return identity.ListCompartmentsResponse{Items: []identity.Compartment{{Id: common.String("child")}}}, nil
+		}
+		return identity.ListCompartmentsResponse{}, nil
+	})
+	table := &textTable{columns: []tableColumn{{header: "NAME"}, {header: "ID"}, {header: "STATE"}, {header: "DESCRIPTION"}}}
+
+	request := identity.ListCompartmentsRequest{
+		CompartmentId:  common.String("root"),
+		LifecycleState: identity.CompartmentLifecycleStateActive,
+	}
+	if err := listCompartmentsRecursive(lister, &request, 0, table); err != nil {
+		t.Fatalf("listCompartmentsRecursive: %v", err)
+	}
+	want := []identity.CompartmentLifecycleStateEnum{identity.CompartmentLifecycleStateActive, identity.CompartmentLifecycleStateActive}
+	if !reflect.DeepEqual(states, want) {
+		t.Errorf("lifecycle states = %v, want %v", states, want)
+	}
+}
+
+// compartmentListerFunc adapts a function to compartmentLister.
+type compartmentListerFunc func(identity.ListCompartmentsRequest) (identity.ListCompartmentsResponse, error)
+
+func (f compartmentListerFunc) ListCompartments(_ context.Context, request identity.ListCompartmentsRequest) (identity.ListCompartmentsResponse, error) {
+	return f(request)
+}
+
+func TestCompartmentCompletions(t *testing.T) {
+	compartment := func(id, parent, name string) identity.Compartment {
+		return identity.Compartment{Id: common.String(id), CompartmentId: common.String(parent), Name: common.String(name)}
+	}
+	compartments := []identity.Compartment{
+		compartment("dev", "tenancy", "dev"),
+		compartment("prod", "tenancy", "prod"),
+		compartment("dev-app", "dev", "app"),
+		compartment("prod-app", "prod", "app"),
+	}
+
+	tests := []struct {
+		toComplete string
+		want       []string
+	}{
+		{toComplete: "", want: []string{"dev\troot/dev", "prod\troot/prod", "root/dev/app\troot/dev/app", "root/prod/app\troot/prod/app"}},
+		{toComplete: "pr", want: []string{"prod\troot/prod"}},
+		{toComplete: "root/d", want: []string{"root/dev/app\troot/dev/app"}},
+		{toComplete: "x", want: nil},
+	}
+	for _, test := range tests {
+		got := compartmentCompletions(compartments, "tenancy", test.toComplete)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("compartmentCompletions(%q) = %q, want %q", test.toComplete, got, test.want)
+		}
+	}
+}
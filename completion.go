@@ -0,0 +1,326 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/spf13/cobra"
+)
+
+func newCompletionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate a shell completion script",
+		Long: `Writes a completion script for the given shell to stdout. For example:
+
+  bash:       source <(oci-cli completion bash)
+  zsh:        oci-cli completion zsh > "${fpath[1]}/_oci-cli"
+  fish:       oci-cli completion fish > ~/.config/fish/completions/oci-cli.fish
+  powershell: oci-cli completion powershell | Out-String | Invoke-Expression
+
+Besides commands and flags, --compartment-id completes compartment names, and --shape-name and
+--image-name complete the shapes and images available in the compartment, all queried from OCI.
+Image names are cached for a few minutes so repeated completions stay fast; --no-cache bypasses
+this cache as well as the compartment cache.`,
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(os.Stdout, true)
+			case "zsh":
+				return root.GenZshCompletion(os.Stdout)
+			case "fish":
+				return root.GenFishCompletion(os.Stdout, true)
+			default:
+				return root.GenPowerShellCompletionWithDesc(os.Stdout)
+			}
+		},
+	}
+}
+
+// flagCompletions maps flag names to the dynamic completion registered on every command that
+// has the flag.
+var flagCompletions = map[string]func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective){
+	"compartment-id": completeCompartmentNames,
+	"shape-name":     completeShapeNames,
+	"image-name":     completeImageNames,
+}
+
+// registerFlagCompletions registers flagCompletions on cmd and all its subcommands. It must run
+// after the command tree is complete.
+func registerFlagCompletions(cmd *cobra.Command) {
+	for name, complete := range flagCompletions {
+		if cmd.Flags().Lookup(name) != nil {
+			_ = cmd.RegisterFlagCompletionFunc(name, complete)
+		}
+	}
+	for _, child := range cmd.Commands() {
+		registerFlagCompletions(child)
+	}
+}
+
+// completeCompartmentNames completes the names of the tenancy's active compartments, using the
+// compartment cache. A name shared by several compartments is offered as its paths instead.
+func completeCompartmentNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	applyCacheFlags(cmd)
+	configProvider, err := newConfigProvider(cmd)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+	tenancyOCID, err := configProvider.TenancyOCID()
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+	client, err := identity.NewIdentityClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+	compartments, _, err := cachedSubtreeCompartments(client, tenancyOCID, false)
+	if err != nil {
+		cobra.CompDebugln(serviceErrorMessage(err), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return compartmentCompletions(filterCompartmentsByState(compartments, identity.CompartmentLifecycleStateActive), tenancyOCID, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// compartmentCompletions returns the sorted "name<TAB>path" completions of the compartments
+// starting with toComplete, using the path for names that are not unique.
+func compartmentCompletions(compartments []identity.Compartment, tenancyOCID, toComplete string) []string {
+	paths := compartmentPaths(compartments, tenancyOCID)
+	nameCounts := map[string]int{}
+	for _, compartment := range compartments {
+		nameCounts[stringValue(compartment.Name)]++
+	}
+
+	var completions []string
+	for _, compartment := range compartments {
+		name, path := stringValue(compartment.Name), paths[*compartment.Id]
+		if nameCounts[name] > 1 {
+			name = path
+		}
+		if strings.HasPrefix(name, toComplete) {
+			completions = append(completions, fmt.Sprintf("%s\t%s", name, path))
+		}
+	}
+	sort.Strings(completions)
+	return completions
+}
+
+// completionCompartmentID resolves the command's --compartment-id (or the profile default) for a
+// completion, falling back to the tenancy.
+func completionCompartmentID(cmd *cobra.Command, configProvider common.ConfigurationProvider) (string, error) {
+	applyProfileDefaults(cmd)
+	if compartmentInput, _ := cmd.Flags().GetString("compartment-id"); compartmentInput != "" {
+		return resolveCompartmentID(compartmentInput, configProvider)
+	}
+	return configProvider.TenancyOCID()
+}
+
+// completeShapeNames completes the shapes available in the command's --compartment-id, or the
+// tenancy when it is not given.
+func completeShapeNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	applyCacheFlags(cmd)
+	configProvider, err := newConfigProvider(cmd)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+	compartmentID, err := completionCompartmentID(cmd, configProvider)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+	client, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	request := core.ListShapesRequest{CompartmentId: &compartmentID, Limit: common.Int(pageSize)}
+	shapes, _, err := collectPages(0, func(page *string) ([]core.Shape, *string, error) {
+		request.Page = page
+		response, err := listShapes(client, request)
+		return response.Items, response.OpcNextPage, err
+	})
+	if err != nil {
+		cobra.CompDebugln(serviceErrorMessage(err), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	// ListShapes returns a shape once per availability domain
+	seen := map[string]bool{}
+	var completions []string
+	for _, shape := range shapes {
+		name := stringValue(shape.Shape)
+		if !seen[name] && strings.HasPrefix(name, toComplete) {
+			seen[name] = true
+			completions = append(completions, name)
+		}
+	}
+	sort.Strings(completions)
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeImageNames completes the names of the images in the command's --compartment-id and the
+// platform images, served from the image name cache when possible.
+func completeImageNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	applyCacheFlags(cmd)
+	configProvider, err := newConfigProvider(cmd)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+	tenancyOCID, err := configProvider.TenancyOCID()
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+	compartmentID, err := completionCompartmentID(cmd, configProvider)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+	client, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	// Platform images are listed under the tenancy, as in resolveImageNameToID
+	compartmentIDs := []string{compartmentID}
+	if compartmentID != tenancyOCID {
+		compartmentIDs = append(compartmentIDs, tenancyOCID)
+	}
+	seen := map[string]bool{}
+	var completions []string
+	for _, id := range compartmentIDs {
+		names, err := cachedImageNames(client, id)
+		if err != nil {
+			cobra.CompDebugln(serviceErrorMessage(err), true)
+			return nil, cobra.ShellCompDirectiveError
+		}
+		for _, name := range names {
+			if !seen[name] && strings.HasPrefix(name, toComplete) {
+				seen[name] = true
+				completions = append(completions, name)
+			}
+		}
+	}
+	sort.Strings(completions)
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// imageNameCacheTTL is how long image names listed for completion are reused. Completion runs
+// a new process for every TAB press, so without it each one would repeat the ListImages calls.
+const imageNameCacheTTL = 5 * time.Minute
+
+// imageNameCacheEntry is the cached list of image names of one compartment.
+type imageNameCacheEntry struct {
+	FetchedAt time.Time `json:"fetchedAt"`
+	Names     []string  `json:"names"`
+}
+
+// cachedImageNames returns the display names of the available images in a compartment, served
+// from the image name cache when an entry younger than imageNameCacheTTL exists. Only --no-cache
+// bypasses it; --cache-ttl applies to the compartment cache alone. Failing to write the cache is
+// not an error.
+func cachedImageNames(client computeAPI, compartmentID string) ([]string, error) {
+	useCache := !cacheDisabled
+	var cache map[string]imageNameCacheEntry
+	if useCache {
+		cache = loadImageNameCache()
+		if entry, ok := cache[compartmentID]; ok && time.Since(entry.FetchedAt) < imageNameCacheTTL {
+			return entry.Names, nil
+		}
+	}
+
+	request := core.ListImagesRequest{
+		CompartmentId:  &compartmentID,
+		LifecycleState: core.ImageLifecycleStateAvailable,
+		Limit:          common.Int(pageSize),
+	}
+	images, _, err := collectPages(0, func(page *string) ([]core.Image, *string, error) {
+		request.Page = page
+		response, err := listImages(client, request)
+		return response.Items, response.OpcNextPage, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(images))
+	for _, image := range images {
+		if name := stringValue(image.DisplayName); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	if useCache {
+		cache[compartmentID] = imageNameCacheEntry{FetchedAt: time.Now(), Names: names}
+		if err := saveImageNameCache(cache); err != nil {
+			slog.Warn("failed to save image name cache", "error", err)
+		}
+	}
+	return names, nil
+}
+
+// imageNameCacheFilePath returns the location of the image name cache file, next to the
+// compartment cache.
+func imageNameCacheFilePath() (string, error) {
+	path, err := compartmentCacheFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "image-names.json"), nil
+}
+
+// loadImageNameCache reads the image name cache file. A missing or corrupt file yields an empty
+// cache.
+func loadImageNameCache() map[string]imageNameCacheEntry {
+	cache := map[string]imageNameCacheEntry{}
+	path, err := imageNameCacheFilePath()
+	if err != nil {
+		return cache
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil || cache == nil {
+		return map[string]imageNameCacheEntry{}
+	}
+	return cache
+}
+
+// saveImageNameCache writes the image name cache file, creating its directory if needed.
+func saveImageNameCache(cache map[string]imageNameCacheEntry) error {
+	path, err := imageNameCacheFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cache file '%s': %w", path, err)
+	}
+	return nil
+}
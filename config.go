@@ -0,0 +1,482 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/common/auth"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/spf13/cobra"
+)
+
+// defaultProfileName is the OCI config profile used when --profile is not given.
+const defaultProfileName = "DEFAULT"
+
+// profileDefaults holds flag defaults stored for a single OCI config profile.
+type profileDefaults struct {
+	CompartmentID string `json:"compartmentId,omitempty"`
+	Region        string `json:"region,omitempty"`
+}
+
+// cliState is the persisted CLI state kept in the state file.
+type cliState struct {
+	Profiles map[string]profileDefaults `json:"profiles,omitempty"`
+}
+
+// regionOverrideProvider wraps a ConfigurationProvider and reports a different region.
+type regionOverrideProvider struct {
+	common.ConfigurationProvider
+	region string
+}
+
+func (p regionOverrideProvider) Region() (string, error) {
+	return p.region, nil
+}
+
+// newConfigProvider builds the ConfigurationProvider for the profile selected with --profile,
+// applying the region given with --region or else the default region stored for that profile.
+// The provider is checked up front so that a missing or incomplete config fails with a clear
+// error instead of on the first API call.
+func newConfigProvider(cmd *cobra.Command) (common.ConfigurationProvider, error) {
+	profileFlag, _ := cmd.Flags().GetString("profile")
+	authFlag, _ := cmd.Flags().GetString("auth")
+
+	var configProvider common.ConfigurationProvider
+	switch authFlag {
+	case "", "config":
+		configFile, custom := configFilePath(cmd)
+		if profileFlag != "" || custom {
+			configProvider = common.CustomProfileConfigProvider(configFile, activeProfileName(cmd))
+		} else {
+			configProvider = common.DefaultConfigProvider()
+		}
+		if ok, err := common.IsConfigurationProviderValid(configProvider); !ok {
+			return nil, fmt.Errorf("invalid OCI configuration (profile '%s', config file '%s'): %w", activeProfileName(cmd), configFile, err)
+		}
+	case "instance-principal":
+		provider, err := auth.InstancePrincipalConfigurationProvider()
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up instance principal authentication: %w", err)
+		}
+		configProvider = provider
+	case "resource-principal":
+		if err := checkResourcePrincipalEnv(); err != nil {
+			return nil, err
+		}
+		provider, err := auth.ResourcePrincipalConfigurationProvider()
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up resource principal authentication: %w", err)
+		}
+		configProvider = provider
+	default:
+		return nil, fmt.Errorf("invalid --auth '%s' (must be 'config', 'instance-principal' or 'resource-principal')", authFlag)
+	}
+
+	if regionFlag, _ := cmd.Flags().GetString("region"); regionFlag != "" {
+		region, err := parseRegion(regionFlag)
+		if err != nil {
+			return nil, err
+		}
+		configProvider = regionOverrideProvider{ConfigurationProvider: configProvider, region: region}
+	} else if defaults := activeProfileDefaults(cmd); defaults.Region != "" {
+		configProvider = regionOverrideProvider{ConfigurationProvider: configProvider, region: defaults.Region}
+	}
+	return configProvider, nil
+}
+
+// parseRegion resolves a --region value, either a region identifier or a short code such as
+// "iad", to its region identifier, rejecting regions the SDK does not know.
+func parseRegion(value string) (string, error) {
+	region := common.StringToRegion(value)
+	if _, err := region.RealmID(); err != nil {
+		return "", fmt.Errorf("unknown --region '%s'", value)
+	}
+	return string(region), nil
+}
+
+// checkResourcePrincipalEnv verifies that the OCI_RESOURCE_PRINCIPAL_* environment variables
+// needed by the resource principal version in use are set, naming every missing one.
+func checkResourcePrincipalEnv() error {
+	version, ok := os.LookupEnv(auth.ResourcePrincipalVersionEnvVar)
+	if !ok {
+		return fmt.Errorf("--auth resource-principal requires %s to be set; it is provided when running inside an OCI Function or a pod with workload identity", auth.ResourcePrincipalVersionEnvVar)
+	}
+
+	var required []string
+	switch version {
+	case auth.ResourcePrincipalVersion2_2:
+		required = []string{auth.ResourcePrincipalRPSTEnvVar, auth.ResourcePrincipalPrivatePEMEnvVar, auth.ResourcePrincipalRegionEnvVar}
+	case auth.ResourcePrincipalVersion1_1:
+		required = []string{auth.ResourcePrincipalTokenEndpoint, auth.ResourcePrincipalSessionTokenEndpoint}
+	default:
+		return fmt.Errorf("unsupported %s '%s' (must be %s or %s)", auth.ResourcePrincipalVersionEnvVar, version, auth.ResourcePrincipalVersion2_2, auth.ResourcePrincipalVersion1_1)
+	}
+
+	var missing []string
+	for _, name := range required {
+		if os.Getenv(name) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("--auth resource-principal (version %s) requires environment variables that are not set: %s", version, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// configFilePath returns the OCI config file to read: --config-file, else $OCI_CLI_CONFIG_FILE,
+// else ~/.oci/config, with a leading "~" expanded. It also reports whether the path differs
+// from the SDK default location.
+func configFilePath(cmd *cobra.Command) (string, bool) {
+	path, _ := cmd.Flags().GetString("config-file")
+	if path == "" {
+		path = os.Getenv("OCI_CLI_CONFIG_FILE")
+	}
+	custom := path != ""
+	if !custom {
+		path = "~/.oci/config"
+	}
+	return expandHome(path), custom
+}
+
+// expandHome replaces a leading "~" in path with the user's home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// activeProfileName returns the profile selected with --profile, or DEFAULT.
+func activeProfileName(cmd *cobra.Command) string {
+	profileFlag, _ := cmd.Flags().GetString("profile")
+	if profileFlag == "" {
+		return defaultProfileName
+	}
+	return profileFlag
+}
+
+// activeProfileDefaults returns the stored defaults of the active profile. A missing or
+// unreadable state file yields empty defaults.
+func activeProfileDefaults(cmd *cobra.Command) profileDefaults {
+	state, err := loadState()
+	if err != nil {
+		return profileDefaults{}
+	}
+	return state.Profiles[activeProfileName(cmd)]
+}
+
+// applyProfileDefaults fills in --compartment-id from the active profile's stored default
+// when the command has that flag and it was not given explicitly.
+func applyProfileDefaults(cmd *cobra.Command) {
+	flag := cmd.Flags().Lookup("compartment-id")
+	if flag == nil || flag.Changed {
+		return
+	}
+	if defaults := activeProfileDefaults(cmd); defaults.CompartmentID != "" {
+		_ = cmd.Flags().Set("compartment-id", defaults.CompartmentID)
+	}
+}
+
+// stateFilePath returns the location of the CLI state file.
+func stateFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".oci-cli", "state.json"), nil
+}
+
+// loadState reads the CLI state file. A missing file is not an error.
+func loadState() (cliState, error) {
+	state := cliState{Profiles: map[string]profileDefaults{}}
+
+	path, err := stateFilePath()
+	if err != nil {
+		return state, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to read state file '%s': %w", path, err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to parse state file '%s': %w", path, err)
+	}
+	if state.Profiles == nil {
+		state.Profiles = map[string]profileDefaults{}
+	}
+	return state, nil
+}
+
+// saveState writes the CLI state file, creating its directory if needed.
+func saveState(state cliState) error {
+	path, err := stateFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write state file '%s': %w", path, err)
+	}
+	return nil
+}
+
+func newConfigCmd() *cobra.Command {
+	var configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Manage CLI configuration and per-profile defaults",
+	}
+
+	var setDefaultCmd = &cobra.Command{
+		Use:   "set-default",
+		Short: "Store default compartment and region for a profile",
+		Long: `Stores defaults for the profile selected with --profile (DEFAULT if omitted).
+The stored compartment is used whenever a command's --compartment-id is not given,
+and the stored region (given with the global --region flag) replaces the region from
+the OCI config file.
+Pass an empty value (e.g. --region "") to clear a stored default.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("compartment") && !cmd.Flags().Changed("region") {
+				return errors.New("specify --compartment and/or --region")
+			}
+
+			state, err := loadState()
+			if err != nil {
+				return err
+			}
+
+			profile := activeProfileName(cmd)
+			defaults := state.Profiles[profile]
+			if cmd.Flags().Changed("compartment") {
+				defaults.CompartmentID, _ = cmd.Flags().GetString("compartment")
+			}
+			if cmd.Flags().Changed("region") {
+				regionFlag, _ := cmd.Flags().GetString("region")
+				defaults.Region = ""
+				if regionFlag != "" {
+					defaults.Region, err = parseRegion(regionFlag)
+					if err != nil {
+						return err
+					}
+				}
+			}
+			if defaults == (profileDefaults{}) {
+				delete(state.Profiles, profile)
+			} else {
+				state.Profiles[profile] = defaults
+			}
+
+			if err := saveState(state); err != nil {
+				return err
+			}
+			fmt.Printf("Defaults for profile '%s': compartment=%q region=%q\n", profile, defaults.CompartmentID, defaults.Region)
+			return nil
+		},
+	}
+
+	setDefaultCmd.Flags().String("compartment", "", "Default compartment OCID, name or path for this profile")
+
+	var showDefaultsCmd = &cobra.Command{
+		Use:   "show-defaults",
+		Short: "Show the stored defaults of every profile",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			state, err := loadState()
+			if err != nil {
+				return err
+			}
+			if len(state.Profiles) == 0 {
+				fmt.Println("No profile defaults stored.")
+				return nil
+			}
+			profiles := make([]string, 0, len(state.Profiles))
+			for profile := range state.Profiles {
+				profiles = append(profiles, profile)
+			}
+			sort.Strings(profiles)
+			for _, profile := range profiles {
+				defaults := state.Profiles[profile]
+				fmt.Printf("Profile: %s\n", profile)
+				fmt.Printf("  Compartment: %s\n", defaults.CompartmentID)
+				fmt.Printf("  Region:      %s\n", defaults.Region)
+			}
+			return nil
+		},
+	}
+
+	var profilesCmd = &cobra.Command{
+		Use:   "profiles",
+		Short: "List the profiles of the OCI config file and check their key files",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configFile, _ := configFilePath(cmd)
+			data, err := os.ReadFile(configFile)
+			if err != nil {
+				return fmt.Errorf("reading OCI config file: %w", err)
+			}
+			profiles, err := parseOCIConfigProfiles(string(data))
+			if err != nil {
+				return fmt.Errorf("parsing OCI config file '%s': %w", configFile, err)
+			}
+			for i := range profiles {
+				profiles[i].KeyStatus = keyFileStatus(profiles[i].KeyFile)
+			}
+
+			if isStructuredOutput(output) {
+				if err := printList(output, profiles, listMeta{Count: len(profiles)}); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			if len(profiles) == 0 {
+				fmt.Printf("No profiles found in %s.\n", configFile)
+				return nil
+			}
+			table := newTextTable(cmd,
+				tableColumn{header: "PROFILE"},
+				tableColumn{header: "REGION"},
+				tableColumn{header: "KEY FILE"},
+				tableColumn{header: "KEY"},
+			)
+			for _, profile := range profiles {
+				table.addRow(profile.Name, profile.Region, profile.KeyFile, profile.KeyStatus)
+			}
+			table.print()
+			return nil
+		},
+	}
+
+	var validateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Check that the selected profile's credentials work",
+		Long: `Builds the configuration of the profile selected with --profile (DEFAULT if omitted)
+and makes a lightweight authenticated call, listing the tenancy's region subscriptions,
+to confirm the credentials are accepted.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile := activeProfileName(cmd)
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return fmt.Errorf("profile '%s' is not valid: %w", profile, err)
+			}
+			tenancyOCID, err := configProvider.TenancyOCID()
+			if err != nil {
+				return fmt.Errorf("profile '%s' is not valid: getting tenancy OCID: %w", profile, err)
+			}
+			client, err := identity.NewIdentityClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("profile '%s' is not valid: creating identity client: %w", profile, err)
+			}
+			response, err := apiCall(client.ListRegionSubscriptions, identity.ListRegionSubscriptionsRequest{TenancyId: &tenancyOCID})
+			if err != nil {
+				return fmt.Errorf("profile '%s' failed to authenticate: %w", profile, err)
+			}
+
+			region, _ := configProvider.Region()
+			fmt.Printf("Profile '%s' is valid: authenticated to tenancy %s in region %s (%d subscribed regions).\n", profile, tenancyOCID, region, len(response.Items))
+			return nil
+		},
+	}
+
+	addNoTruncateFlag(profilesCmd)
+
+	configCmd.AddCommand(setDefaultCmd, showDefaultsCmd, profilesCmd, validateCmd)
+
+	return configCmd
+}
+
+// ociConfigProfile is one profile of an OCI config file, with values inherited from DEFAULT.
+type ociConfigProfile struct {
+	Name      string `json:"name"`
+	Region    string `json:"region,omitempty"`
+	Tenancy   string `json:"tenancy,omitempty"`
+	User      string `json:"user,omitempty"`
+	KeyFile   string `json:"keyFile,omitempty"`
+	KeyStatus string `json:"keyStatus"`
+}
+
+// parseOCIConfigProfiles parses the INI-style OCI config file format into its profiles, in file
+// order. As in the SDK, keys missing from a profile fall back to the DEFAULT profile's.
+func parseOCIConfigProfiles(data string) ([]ociConfigProfile, error) {
+	var names []string
+	values := map[string]map[string]string{}
+	current := ""
+	for lineNumber, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = strings.TrimSpace(line[1 : len(line)-1])
+			if _, ok := values[current]; !ok {
+				names = append(names, current)
+				values[current] = map[string]string{}
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected 'key=value' or '[PROFILE]', got '%s'", lineNumber+1, line)
+		}
+		if current == "" {
+			return nil, fmt.Errorf("line %d: '%s' appears before any [PROFILE] header", lineNumber+1, strings.TrimSpace(key))
+		}
+		values[current][strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	profiles := make([]ociConfigProfile, len(names))
+	for i, name := range names {
+		lookup := func(key string) string {
+			if value, ok := values[name][key]; ok {
+				return value
+			}
+			return values[defaultProfileName][key]
+		}
+		profiles[i] = ociConfigProfile{
+			Name:    name,
+			Region:  lookup("region"),
+			Tenancy: lookup("tenancy"),
+			User:    lookup("user"),
+			KeyFile: lookup("key_file"),
+		}
+	}
+	return profiles, nil
+}
+
+// keyFileStatus reports whether a profile's key file exists and is readable: "ok", "not set",
+// "missing" or "unreadable".
+func keyFileStatus(path string) string {
+	if path == "" {
+		return "not set"
+	}
+	file, err := os.Open(expandHome(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return "missing"
+	}
+	if err != nil {
+		return "unreadable"
+	}
+	file.Close()
+	return "ok"
+}
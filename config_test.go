@@ -0,0 +1,42 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseOCIConfigProfiles(t *testing.T) {
+	data := `# OCI config
+[DEFAULT]
+user=ocid1.user.oc1..default
+tenancy=ocid1.tenancy.oc1..t
+region=us-ashburn-1
+key_file=~/.oci/oci_api_key.pem
+
+[dev]
+region = eu-frankfurt-1
+; inherits user, tenancy and key_file
+[ops]
+user=ocid1.user.oc1..ops
+key_file=/keys/ops.pem
+`
+	profiles, err := parseOCIConfigProfiles(data)
+	if err != nil {
+		t.Fatalf("parseOCIConfigProfiles() error = %v", err)
+	}
+	want := []ociConfigProfile{
+		{Name: "DEFAULT", Region: "us-ashburn-1", Tenancy: "ocid1.tenancy.oc1..t", User: "ocid1.user.oc1..default", KeyFile: "~/.oci/oci_api_key.pem"},
+		{Name: "dev", Region: "eu-frankfurt-1", Tenancy: "ocid1.tenancy.oc1..t", User: "ocid1.user.oc1..default", KeyFile: "~/.oci/oci_api_key.pem"},
+		{Name: "ops", Region: "us-ashburn-1", Tenancy: "ocid1.tenancy.oc1..t", User: "ocid1.user.oc1..ops", KeyFile: "/keys/ops.pem"},
+	}
+	if !reflect.DeepEqual(profiles, want) {
+		t.Errorf("parseOCIConfigProfiles() = %+v, want %+v", profiles, want)
+	}
+
+	for _, invalid := range []string{"region=us-ashburn-1\n[DEFAULT]\n", "[DEFAULT]\nnot a key value\n"} {
+		if _, err := parseOCIConfigProfiles(invalid); err == nil || !strings.Contains(err.Error(), "line 1") && !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("parseOCIConfigProfiles(%q) error = %v, want a line number", invalid, err)
+		}
+	}
+}
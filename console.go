@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/spf13/cobra"
+)
+
+func newConsoleConnectionCmd() *cobra.Command {
+	var consoleConnectionCmd = &cobra.Command{
+		Use:   "console-connection",
+		Short: "Manage instance console connections (serial console and VNC over SSH)",
+	}
+
+	var createCmd = &cobra.Command{
+		Use:   "create",
+		Short: "Create a console connection to an instance",
+		Long: `Creates a console connection for debugging an instance that does not boot or is not
+reachable over the network. Once the connection is ACTIVE, the SSH command for the serial
+console and the SSH tunnel command for VNC are printed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			instanceIDFlag, _ := cmd.Flags().GetString("instance-id")
+			publicKeyFlag, _ := cmd.Flags().GetString("public-key")
+			waitTimeoutFlag, _ := cmd.Flags().GetInt("wait-timeout")
+
+			publicKey, err := readPublicKey(publicKeyFlag)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating compute client: %w", err)
+			}
+
+			response, err := apiCall(computeClient.CreateInstanceConsoleConnection, core.CreateInstanceConsoleConnectionRequest{
+				CreateInstanceConsoleConnectionDetails: core.CreateInstanceConsoleConnectionDetails{
+					InstanceId: &instanceIDFlag,
+					PublicKey:  &publicKey,
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("creating console connection: %w", err)
+			}
+			connection := response.InstanceConsoleConnection
+			fmt.Printf("Console connection created.\nConnection ID: %s\nState: %s\n", stringValue(connection.Id), connection.LifecycleState)
+
+			if connection.LifecycleState != core.InstanceConsoleConnectionLifecycleStateActive {
+				fmt.Println("Waiting for the console connection to become ACTIVE...")
+				connection, err = waitForConsoleConnection(computeClient, stringValue(connection.Id), time.Duration(waitTimeoutFlag)*time.Second)
+				if err != nil {
+					return err
+				}
+			}
+
+			fmt.Printf("\nSerial console (SSH):\n  %s\n", stringValue(connection.ConnectionString))
+			fmt.Printf("\nVNC (run this, then point a VNC client at localhost:5900):\n  %s\n", stringValue(connection.VncConnectionString))
+			if connection.ServiceHostKeyFingerprint != nil {
+				fmt.Printf("\nService host key fingerprint: %s\n", *connection.ServiceHostKeyFingerprint)
+			}
+			return nil
+		},
+	}
+
+	createCmd.Flags().String("instance-id", "", "OCID of the instance (Required)")
+	createCmd.Flags().String("public-key", "", "SSH public key, or the path of a public key file, used to authenticate the connection (Required)")
+	createCmd.Flags().Int("wait-timeout", 120, "(Optional) Maximum number of seconds to wait for the connection to become ACTIVE")
+	_ = createCmd.MarkFlagRequired("instance-id")
+	_ = createCmd.MarkFlagRequired("public-key")
+
+	var deleteCmd = &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a console connection",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			idFlag, _ := cmd.Flags().GetString("id")
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating compute client: %w", err)
+			}
+
+			_, err = apiCall(computeClient.DeleteInstanceConsoleConnection, core.DeleteInstanceConsoleConnectionRequest{
+				InstanceConsoleConnectionId: &idFlag,
+			})
+			if err != nil {
+				return fmt.Errorf("deleting console connection: %w", err)
+			}
+			fmt.Printf("Console connection %s deleted.\n", idFlag)
+			return nil
+		},
+	}
+
+	deleteCmd.Flags().String("id", "", "OCID of the console connection (Required)")
+	_ = deleteCmd.MarkFlagRequired("id")
+
+	consoleConnectionCmd.AddCommand(createCmd, deleteCmd)
+
+	return consoleConnectionCmd
+}
+
+// maxConsoleHistoryLength is the largest amount of console history the service returns.
+const maxConsoleHistoryLength = 1024 * 1024
+
+func newGetConsoleHistoryCmd() *cobra.Command {
+	var getConsoleHistoryCmd = &cobra.Command{
+		Use:   "get-console-history",
+		Short: "Capture and print the serial console output of an instance",
+		Long: `Captures the recent serial console output of an instance and prints it, or writes it to
+--file. The capture runs asynchronously; the command waits until it has SUCCEEDED.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			lengthFlag, _ := cmd.Flags().GetInt("length")
+			fileFlag, _ := cmd.Flags().GetString("file")
+			waitTimeoutFlag, _ := cmd.Flags().GetInt("wait-timeout")
+
+			if lengthFlag <= 0 || lengthFlag > maxConsoleHistoryLength {
+				return fmt.Errorf("invalid --length %d (must be between 1 and %d)", lengthFlag, maxConsoleHistoryLength)
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating compute client: %w", err)
+			}
+
+			instanceID, err := resolveInstanceSelector(cmd, configProvider, computeClient)
+			if err != nil {
+				return err
+			}
+
+			response, err := apiCall(computeClient.CaptureConsoleHistory, core.CaptureConsoleHistoryRequest{
+				CaptureConsoleHistoryDetails: core.CaptureConsoleHistoryDetails{InstanceId: &instanceID},
+			})
+			if err != nil {
+				return fmt.Errorf("capturing console history: %w", err)
+			}
+			historyID := stringValue(response.ConsoleHistory.Id)
+			if err := waitForConsoleHistory(computeClient, historyID, time.Duration(waitTimeoutFlag)*time.Second); err != nil {
+				return err
+			}
+
+			content, err := apiCall(computeClient.GetConsoleHistoryContent, core.GetConsoleHistoryContentRequest{
+				InstanceConsoleHistoryId: &historyID,
+				Length:                   &lengthFlag,
+			})
+			if err != nil {
+				return fmt.Errorf("getting console history content: %w", err)
+			}
+			history := stringValue(content.Value)
+
+			if fileFlag == "" {
+				fmt.Print(history)
+				return nil
+			}
+			if err := os.WriteFile(fileFlag, []byte(history), 0644); err != nil {
+				return fmt.Errorf("writing console history to '%s': %w", fileFlag, err)
+			}
+			fmt.Printf("Wrote %d bytes of console history to %s\n", len(history), fileFlag)
+			return nil
+		},
+	}
+
+	addInstanceSelectorFlags(getConsoleHistoryCmd, "read the console of")
+	getConsoleHistoryCmd.Flags().Int("length", maxConsoleHistoryLength, "(Optional) Maximum number of bytes of console history to fetch")
+	getConsoleHistoryCmd.Flags().String("file", "", "(Optional) Write the console history to this file instead of stdout")
+	getConsoleHistoryCmd.Flags().Int("wait-timeout", 120, "(Optional) Maximum number of seconds to wait for the capture to finish")
+
+	return getConsoleHistoryCmd
+}
+
+// waitForConsoleHistory polls a console history capture until it has SUCCEEDED, fails, or timeout elapses.
+func waitForConsoleHistory(client core.ComputeClient, historyID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		response, err := apiCall(client.GetConsoleHistory, core.GetConsoleHistoryRequest{InstanceConsoleHistoryId: &historyID})
+		if err != nil {
+			return fmt.Errorf("failed to get console history: %w", err)
+		}
+		switch response.ConsoleHistory.LifecycleState {
+		case core.ConsoleHistoryLifecycleStateSucceeded:
+			return nil
+		case core.ConsoleHistoryLifecycleStateFailed:
+			return fmt.Errorf("console history capture %s failed", historyID)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for the console history capture (last state: %s)", timeout, response.ConsoleHistory.LifecycleState)
+		}
+		pause(2 * time.Second)
+	}
+}
+
+// readPublicKey returns an SSH public key given either inline or as the path of a key file.
+func readPublicKey(value string) (string, error) {
+	if strings.HasPrefix(value, "ssh-") || strings.HasPrefix(value, "ecdsa-") {
+		return strings.TrimSpace(value), nil
+	}
+	data, err := os.ReadFile(expandHome(value))
+	if err != nil {
+		return "", fmt.Errorf("reading public key file '%s': %w", value, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// waitForConsoleConnection polls a console connection until it is ACTIVE, fails, or timeout elapses.
+func waitForConsoleConnection(client core.ComputeClient, connectionID string, timeout time.Duration) (core.InstanceConsoleConnection, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		response, err := apiCall(client.GetInstanceConsoleConnection, core.GetInstanceConsoleConnectionRequest{
+			InstanceConsoleConnectionId: &connectionID,
+		})
+		if err != nil {
+			return core.InstanceConsoleConnection{}, fmt.Errorf("failed to get console connection: %w", err)
+		}
+		connection := response.InstanceConsoleConnection
+		switch connection.LifecycleState {
+		case core.InstanceConsoleConnectionLifecycleStateActive:
+			return connection, nil
+		case core.InstanceConsoleConnectionLifecycleStateFailed, core.InstanceConsoleConnectionLifecycleStateDeleting, core.InstanceConsoleConnectionLifecycleStateDeleted:
+			return connection, fmt.Errorf("console connection entered state %s", connection.LifecycleState)
+		}
+		if time.Now().After(deadline) {
+			return connection, fmt.Errorf("timed out after %s waiting for the console connection (last state: %s)", timeout, connection.LifecycleState)
+		}
+		pause(3 * time.Second)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/spf13/cobra"
+)
+
+// instanceRecord is an instance together with the details --fields may select beyond the
+// instance itself.
+type instanceRecord struct {
+	instance        core.Instance
+	compartmentName string
+	vnics           []vnicJSON
+}
+
+// instanceField is a column selectable with --fields on instances list and info.
+type instanceField struct {
+	name   string
+	column tableColumn
+	vnics  bool // the value needs the instance's VNICs to be resolved
+	value  func(record instanceRecord) string
+}
+
+// instanceFields are the fields --fields accepts, in the order they are listed in help and errors.
+var instanceFields = []instanceField{
+	{name: "id", column: tableColumn{header: "ID", ocid: true}, value: func(r instanceRecord) string { return stringValue(r.instance.Id) }},
+	{name: "name", column: tableColumn{header: "DISPLAY NAME"}, value: func(r instanceRecord) string { return stringValue(r.instance.DisplayName) }},
+	{name: "state", column: tableColumn{header: "STATE"}, value: func(r instanceRecord) string { return string(r.instance.LifecycleState) }},
+	{name: "created", column: tableColumn{header: "CREATED"}, value: func(r instanceRecord) string { return formatSDKTime(r.instance.TimeCreated) }},
+	{name: "shape", column: tableColumn{header: "SHAPE"}, value: func(r instanceRecord) string { return stringValue(r.instance.Shape) }},
+	{name: "ocpus", column: tableColumn{header: "OCPUS", numeric: true}, value: func(r instanceRecord) string {
+		if r.instance.ShapeConfig == nil || r.instance.ShapeConfig.Ocpus == nil {
+			return ""
+		}
+		return fmt.Sprintf("%g", *r.instance.ShapeConfig.Ocpus)
+	}},
+	{name: "memory", column: tableColumn{header: "MEMORY (GB)", numeric: true}, value: func(r instanceRecord) string {
+		if r.instance.ShapeConfig == nil || r.instance.ShapeConfig.MemoryInGBs == nil {
+			return ""
+		}
+		return fmt.Sprintf("%g", *r.instance.ShapeConfig.MemoryInGBs)
+	}},
+	{name: "image-id", column: tableColumn{header: "IMAGE ID", ocid: true}, value: func(r instanceRecord) string { return stringValue(r.instance.ImageId) }},
+	{name: "compartment", column: tableColumn{header: "COMPARTMENT"}, value: func(r instanceRecord) string { return r.compartmentName }},
+	{name: "compartment-id", column: tableColumn{header: "COMPARTMENT ID", ocid: true}, value: func(r instanceRecord) string { return stringValue(r.instance.CompartmentId) }},
+	{name: "availability-domain", column: tableColumn{header: "AVAILABILITY DOMAIN"}, value: func(r instanceRecord) string { return stringValue(r.instance.AvailabilityDomain) }},
+	{name: "fault-domain", column: tableColumn{header: "FAULT DOMAIN"}, value: func(r instanceRecord) string { return stringValue(r.instance.FaultDomain) }},
+	{name: "private-ip", column: tableColumn{header: "PRIVATE IPS"}, vnics: true, value: func(r instanceRecord) string {
+		var ips []string
+		for _, vnic := range r.vnics {
+			if vnic.PrivateIP != "" {
+				ips = append(ips, vnic.PrivateIP)
+			}
+		}
+		return strings.Join(ips, ",")
+	}},
+	{name: "public-ip", column: tableColumn{header: "PUBLIC IPS"}, vnics: true, value: func(r instanceRecord) string {
+		var ips []string
+		for _, vnic := range r.vnics {
+			if vnic.PublicIP != "" {
+				ips = append(ips, vnic.PublicIP)
+			}
+		}
+		return strings.Join(ips, ",")
+	}},
+}
+
+// instanceFieldNames returns the names of all instanceFields.
+func instanceFieldNames() []string {
+	names := make([]string, len(instanceFields))
+	for i, field := range instanceFields {
+		names[i] = field.name
+	}
+	return names
+}
+
+// addFieldsFlag registers the --fields flag on an instance command.
+func addFieldsFlag(cmd *cobra.Command) {
+	cmd.Flags().String("fields", "", fmt.Sprintf("(Optional) Comma-separated fields to print instead of the default columns: %s", strings.Join(instanceFieldNames(), ", ")))
+}
+
+// parseInstanceFields resolves a comma-separated --fields value. It returns nil for an empty
+// value, meaning the command's default output.
+func parseInstanceFields(input string) ([]instanceField, error) {
+	var fields []instanceField
+	for _, name := range strings.Split(input, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, field := range instanceFields {
+			if field.name == name {
+				fields = append(fields, field)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid --fields entry '%s' (must be one of %s)", name, strings.Join(instanceFieldNames(), ", "))
+		}
+	}
+	return fields, nil
+}
+
+// fieldsNeedVnics reports whether any of the fields needs the instances' VNICs.
+func fieldsNeedVnics(fields []instanceField) bool {
+	for _, field := range fields {
+		if field.vnics {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldValues is the structured output of an instance projected onto --fields. It encodes as a
+// JSON object whose keys follow the order of the fields, so YAML and CSV columns do too.
+type fieldValues struct {
+	names  []string
+	values []string
+}
+
+func projectInstance(fields []instanceField, record instanceRecord) fieldValues {
+	projected := fieldValues{names: make([]string, len(fields)), values: make([]string, len(fields))}
+	for i, field := range fields {
+		projected.names[i] = field.name
+		projected.values[i] = field.value(record)
+	}
+	return projected
+}
+
+func (v fieldValues) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, name := range v.names {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(v.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// printInstanceFieldsTable writes a table of the selected fields for the given records, showing
+// "-" for empty values.
+func printInstanceFieldsTable(cmd *cobra.Command, fields []instanceField, records []instanceRecord) {
+	columns := make([]tableColumn, len(fields))
+	for i, field := range fields {
+		columns[i] = field.column
+	}
+	table := newTextTable(cmd, columns...)
+	for _, record := range records {
+		row := make([]string, len(fields))
+		for i, field := range fields {
+			row[i] = field.value(record)
+			if row[i] == "" {
+				row[i] = "-"
+			}
+		}
+		table.addRow(row...)
+	}
+	table.print()
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func TestParseInstanceFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr string
+	}{
+		{name: "empty", input: "", want: nil},
+		{name: "selection keeps order", input: "state, ID,private-ip", want: []string{"state", "id", "private-ip"}},
+		{name: "unknown field", input: "id,nmae", wantErr: "invalid --fields entry 'nmae' (must be one of id, name,"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fields, err := parseInstanceFields(test.input)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("parseInstanceFields() error = %v, want it to contain %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseInstanceFields() error = %v", err)
+			}
+			var got []string
+			for _, field := range fields {
+				got = append(got, field.name)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parseInstanceFields() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestProjectInstance(t *testing.T) {
+	fields, err := parseInstanceFields("state,name,private-ip,ocpus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fieldsNeedVnics(fields) {
+		t.Error("fieldsNeedVnics() = false, want true for private-ip")
+	}
+	record := instanceRecord{
+		instance: core.Instance{DisplayName: common.String("web"), LifecycleState: core.InstanceLifecycleStateRunning},
+		vnics:    []vnicJSON{{PrivateIP: "10.0.0.2"}, {PrivateIP: "10.0.1.2"}},
+	}
+	data, err := json.Marshal(projectInstance(fields, record))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"state":"RUNNING","name":"web","private-ip":"10.0.0.2,10.0.1.2","ocpus":""}`
+	if string(data) != want {
+		t.Errorf("projectInstance() = %s, want %s", data, want)
+	}
+}
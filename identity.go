@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/spf13/cobra"
+)
+
+func newIdentityCmd() *cobra.Command {
+	var identityCmd = &cobra.Command{
+		Use:   "identity",
+		Short: "Show tenancy and identity information",
+	}
+
+	var tenancyCmd = &cobra.Command{
+		Use:   "tenancy",
+		Short: "Show a summary of the configured tenancy",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			tenancyOCID, err := configProvider.TenancyOCID()
+			if err != nil {
+				return fmt.Errorf("getting tenancy OCID: %w", err)
+			}
+
+			identityClient, err := identity.NewIdentityClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating identity client: %w", err)
+			}
+
+			response, err := apiCall(identityClient.GetTenancy, identity.GetTenancyRequest{TenancyId: &tenancyOCID})
+			if err != nil {
+				return fmt.Errorf("getting tenancy: %w", err)
+			}
+
+			details := newTenancyJSON(response.Tenancy)
+			if isStructuredOutput(output) {
+				if err := printStructured(output, details); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+
+			fmt.Println("Tenancy Details:")
+			fmt.Printf("  Name: %s\n", details.Name)
+			fmt.Printf("  Description: %s\n", details.Description)
+			fmt.Printf("  Home Region Key: %s\n", details.HomeRegionKey)
+			fmt.Printf("  OCID: %s\n", details.ID)
+			return nil
+		},
+	}
+
+	var regionsCmd = &cobra.Command{
+		Use:   "regions",
+		Short: "Show OCI regions and the tenancy's region subscriptions",
+	}
+
+	var listRegionsCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List all regions, marking the ones the tenancy is subscribed to and its home region",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			subscribedFlag, _ := cmd.Flags().GetBool("subscribed")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			tenancyOCID, err := configProvider.TenancyOCID()
+			if err != nil {
+				return fmt.Errorf("getting tenancy OCID: %w", err)
+			}
+
+			identityClient, err := identity.NewIdentityClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating identity client: %w", err)
+			}
+
+			ctx, cancel := apiContext()
+			defer cancel()
+			regionsResponse, err := identityClient.ListRegions(ctx)
+			if err != nil {
+				return fmt.Errorf("listing regions: %w", err)
+			}
+			subscriptionsResponse, err := apiCall(identityClient.ListRegionSubscriptions, identity.ListRegionSubscriptionsRequest{TenancyId: &tenancyOCID})
+			if err != nil {
+				return fmt.Errorf("listing region subscriptions: %w", err)
+			}
+
+			regions := mergeRegionSubscriptions(regionsResponse.Items, subscriptionsResponse.Items)
+			if subscribedFlag {
+				var subscribed []regionJSON
+				for _, region := range regions {
+					if region.Subscribed {
+						subscribed = append(subscribed, region)
+					}
+				}
+				regions = subscribed
+			}
+
+			currentRegion, _ := configProvider.Region()
+			if isStructuredOutput(output) {
+				if err := printList(output, regions, listMeta{Count: len(regions), Region: currentRegion}); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			table := newTextTable(cmd,
+				tableColumn{header: "REGION"},
+				tableColumn{header: "KEY"},
+				tableColumn{header: "SUBSCRIBED"},
+				tableColumn{header: "STATUS"},
+				tableColumn{header: "HOME"},
+			)
+			for _, region := range regions {
+				subscribed, home := "no", ""
+				if region.Subscribed {
+					subscribed = "yes"
+				}
+				if region.IsHomeRegion {
+					home = "yes"
+				}
+				table.addRow(region.Name, region.Key, subscribed, region.Status, home)
+			}
+			table.print()
+			fmt.Printf("\nCurrent region: %s\n", currentRegion)
+			return nil
+		},
+	}
+
+	listRegionsCmd.Flags().Bool("subscribed", false, "(Optional) Only list regions the tenancy is subscribed to")
+
+	regionsCmd.AddCommand(listRegionsCmd)
+
+	var whoamiCmd = &cobra.Command{
+		Use:   "whoami",
+		Short: "Show which user, tenancy and key the active credentials belong to",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			authFlag, _ := cmd.Flags().GetString("auth")
+			details := whoamiJSON{Profile: activeProfileName(cmd), Auth: authFlag}
+			if details.Auth == "" {
+				details.Auth = "config"
+			}
+			details.TenancyID, err = configProvider.TenancyOCID()
+			if err != nil {
+				return fmt.Errorf("getting tenancy OCID: %w", err)
+			}
+			// Instance and resource principals have no user or API key fingerprint
+			details.UserID, _ = configProvider.UserOCID()
+			details.Fingerprint, _ = configProvider.KeyFingerprint()
+			details.Region, _ = configProvider.Region()
+
+			identityClient, err := identity.NewIdentityClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating identity client: %w", err)
+			}
+
+			tenancyResponse, err := apiCall(identityClient.GetTenancy, identity.GetTenancyRequest{TenancyId: &details.TenancyID})
+			if err != nil {
+				return fmt.Errorf("getting tenancy: %w", err)
+			}
+			details.TenancyName = stringValue(tenancyResponse.Tenancy.Name)
+			details.HomeRegion = stringValue(tenancyResponse.Tenancy.HomeRegionKey)
+
+			// The tenancy only reports its home region key; the subscriptions give its name
+			subscriptionsResponse, err := apiCall(identityClient.ListRegionSubscriptions, identity.ListRegionSubscriptionsRequest{TenancyId: &details.TenancyID})
+			if err != nil {
+				return fmt.Errorf("listing region subscriptions: %w", err)
+			}
+			for _, subscription := range subscriptionsResponse.Items {
+				if subscription.IsHomeRegion != nil && *subscription.IsHomeRegion {
+					details.HomeRegion = stringValue(subscription.RegionName)
+				}
+			}
+
+			if isStructuredOutput(output) {
+				if err := printStructured(output, details); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			fmt.Printf("Profile: %s (auth: %s)\n", details.Profile, details.Auth)
+			fmt.Printf("User: %s\n", valueOrNA(details.UserID))
+			fmt.Printf("Fingerprint: %s\n", valueOrNA(details.Fingerprint))
+			fmt.Printf("Tenancy: %s (%s)\n", details.TenancyName, details.TenancyID)
+			fmt.Printf("Home Region: %s\n", details.HomeRegion)
+			fmt.Printf("Current Region: %s\n", details.Region)
+			return nil
+		},
+	}
+
+	identityCmd.AddCommand(tenancyCmd, regionsCmd, whoamiCmd)
+
+	return identityCmd
+}
+
+// tenancyJSON is the serializable projection of a tenancy.
+type tenancyJSON struct {
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	HomeRegionKey string `json:"homeRegionKey"`
+	ID            string `json:"id"`
+}
+
+func newTenancyJSON(tenancy identity.Tenancy) tenancyJSON {
+	return tenancyJSON{
+		Name:          stringValue(tenancy.Name),
+		Description:   stringValue(tenancy.Description),
+		HomeRegionKey: stringValue(tenancy.HomeRegionKey),
+		ID:            stringValue(tenancy.Id),
+	}
+}
+
+// regionJSON is the serializable projection of a region and the tenancy's subscription to it.
+type regionJSON struct {
+	Name         string `json:"name"`
+	Key          string `json:"key"`
+	Subscribed   bool   `json:"subscribed"`
+	Status       string `json:"status,omitempty"`
+	IsHomeRegion bool   `json:"isHomeRegion"`
+}
+
+// mergeRegionSubscriptions combines the list of all regions with the tenancy's subscriptions,
+// sorted by region name. Subscribed regions missing from the region list are kept.
+func mergeRegionSubscriptions(regions []identity.Region, subscriptions []identity.RegionSubscription) []regionJSON {
+	byName := map[string]*regionJSON{}
+	var result []*regionJSON
+	for _, region := range regions {
+		entry := &regionJSON{Name: stringValue(region.Name), Key: stringValue(region.Key)}
+		byName[entry.Name] = entry
+		result = append(result, entry)
+	}
+	for _, subscription := range subscriptions {
+		name := stringValue(subscription.RegionName)
+		entry, ok := byName[name]
+		if !ok {
+			entry = &regionJSON{Name: name, Key: stringValue(subscription.RegionKey)}
+			byName[name] = entry
+			result = append(result, entry)
+		}
+		entry.Subscribed = true
+		entry.Status = string(subscription.Status)
+		entry.IsHomeRegion = subscription.IsHomeRegion != nil && *subscription.IsHomeRegion
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	merged := make([]regionJSON, len(result))
+	for i, entry := range result {
+		merged[i] = *entry
+	}
+	return merged
+}
+
+// whoamiJSON is the serializable identity of the active credentials.
+type whoamiJSON struct {
+	Profile     string `json:"profile"`
+	Auth        string `json:"auth"`
+	UserID      string `json:"userId,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	TenancyID   string `json:"tenancyId"`
+	TenancyName string `json:"tenancyName"`
+	HomeRegion  string `json:"homeRegion"`
+	Region      string `json:"region"`
+}
+
+// valueOrNA returns value, or "n/a" when it is empty.
+func valueOrNA(value string) string {
+	if value == "" {
+		return "n/a"
+	}
+	return value
+}
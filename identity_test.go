@@ -0,0 +1,32 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+)
+
+func TestMergeRegionSubscriptions(t *testing.T) {
+	regions := []identity.Region{
+		{Name: common.String("us-phoenix-1"), Key: common.String("PHX")},
+		{Name: common.String("eu-frankfurt-1"), Key: common.String("FRA")},
+		{Name: common.String("us-ashburn-1"), Key: common.String("IAD")},
+	}
+	subscriptions := []identity.RegionSubscription{
+		{RegionName: common.String("us-ashburn-1"), RegionKey: common.String("IAD"), Status: identity.RegionSubscriptionStatusReady, IsHomeRegion: common.Bool(true)},
+		{RegionName: common.String("eu-frankfurt-1"), RegionKey: common.String("FRA"), Status: identity.RegionSubscriptionStatusInProgress, IsHomeRegion: common.Bool(false)},
+		{RegionName: common.String("ap-new-1"), RegionKey: common.String("NEW"), Status: identity.RegionSubscriptionStatusReady},
+	}
+
+	want := []regionJSON{
+		{Name: "ap-new-1", Key: "NEW", Subscribed: true, Status: "READY"},
+		{Name: "eu-frankfurt-1", Key: "FRA", Subscribed: true, Status: "IN_PROGRESS"},
+		{Name: "us-ashburn-1", Key: "IAD", Subscribed: true, Status: "READY", IsHomeRegion: true},
+		{Name: "us-phoenix-1", Key: "PHX"},
+	}
+	if got := mergeRegionSubscriptions(regions, subscriptions); !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeRegionSubscriptions() = %+v, want %+v", got, want)
+	}
+}
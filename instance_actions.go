@@ -0,0 +1,757 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/oracle/oci-go-sdk/v65/workrequests"
+	"github.com/spf13/cobra"
+)
+
+// autoStopTag is the freeform tag holding the RFC3339 time after which `instances run-scheduled` stops an instance.
+const autoStopTag = "auto_stop_at"
+
+func newStopCmd() *cobra.Command {
+	var stopCmd = &cobra.Command{
+		Use:   "stop",
+		Short: "Stop a compute instance, now or at a scheduled time",
+		Long: `Stops a compute instance.
+
+With --schedule the instance is not stopped immediately. Instead it is tagged with
+` + autoStopTag + ` and stopped by the next 'instances run-scheduled' run (e.g. from cron)
+after that time has passed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scheduleFlag, _ := cmd.Flags().GetString("schedule")
+			actionFlag, _ := cmd.Flags().GetString("action")
+
+			action, ok := core.GetMappingInstanceActionActionEnum(actionFlag)
+			if !ok || (action != core.InstanceActionActionSoftstop && action != core.InstanceActionActionStop) {
+				return fmt.Errorf("invalid --action '%s' (must be SOFTSTOP or STOP)", actionFlag)
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating compute client: %w", err)
+			}
+
+			instanceID, err := resolveInstanceSelector(cmd, configProvider, computeClient)
+			if err != nil {
+				return err
+			}
+
+			if scheduleFlag != "" {
+				stopAt, err := parseScheduleTime(scheduleFlag, time.Now())
+				if err != nil {
+					return err
+				}
+				if err := setInstanceFreeformTag(computeClient, instanceID, autoStopTag, stopAt.Format(time.RFC3339)); err != nil {
+					return fmt.Errorf("scheduling stop: %w", err)
+				}
+				fmt.Printf("Instance %s scheduled to stop at %s.\n", instanceID, stopAt.Format(time.RFC3339))
+				fmt.Println("Note: Run 'instances run-scheduled' periodically (e.g. from cron) to perform scheduled actions.")
+				return nil
+			}
+
+			current, err := getInstance(computeClient, core.GetInstanceRequest{InstanceId: &instanceID})
+			if err != nil {
+				return fmt.Errorf("getting instance: %w", err)
+			}
+			if state := current.Instance.LifecycleState; state == core.InstanceLifecycleStateTerminating || state == core.InstanceLifecycleStateTerminated {
+				return fmt.Errorf("instance %s is %s and cannot be stopped", instanceID, state)
+			}
+
+			response, err := apiCall(computeClient.InstanceAction, core.InstanceActionRequest{
+				InstanceId: &instanceID,
+				Action:     action,
+			})
+			if err != nil {
+				return fmt.Errorf("stopping instance: %w", err)
+			}
+			fmt.Printf("%s initiated for instance %s.\nState: %s\n", action, instanceID, response.Instance.LifecycleState)
+			return nil
+		},
+	}
+
+	addInstanceSelectorFlags(stopCmd, "stop")
+	stopCmd.Flags().String("action", "SOFTSTOP", "Stop action: SOFTSTOP (graceful shutdown) or STOP (immediate power off)")
+	stopCmd.Flags().String("schedule", "", "(Optional) Schedule the stop instead of stopping now: an RFC3339 time or a delay such as '2h30m'")
+
+	return stopCmd
+}
+
+func newStartCmd() *cobra.Command {
+	var startCmd = &cobra.Command{
+		Use:   "start",
+		Short: "Start a stopped compute instance",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating compute client: %w", err)
+			}
+
+			instanceID, err := resolveInstanceSelector(cmd, configProvider, computeClient)
+			if err != nil {
+				return err
+			}
+
+			current, err := getInstance(computeClient, core.GetInstanceRequest{InstanceId: &instanceID})
+			if err != nil {
+				return fmt.Errorf("getting instance: %w", err)
+			}
+			if current.Instance.LifecycleState == core.InstanceLifecycleStateRunning {
+				fmt.Printf("Instance %s is already RUNNING.\n", instanceID)
+				return nil
+			}
+
+			response, err := apiCall(computeClient.InstanceAction, core.InstanceActionRequest{
+				InstanceId: &instanceID,
+				Action:     core.InstanceActionActionStart,
+			})
+			if err != nil {
+				return fmt.Errorf("starting instance: %w", err)
+			}
+			fmt.Printf("Start initiated for instance %s.\nState: %s\n", instanceID, response.Instance.LifecycleState)
+			return nil
+		},
+	}
+
+	addInstanceSelectorFlags(startCmd, "start")
+
+	return startCmd
+}
+
+func newTerminateCmd() *cobra.Command {
+	var terminateCmd = &cobra.Command{
+		Use:   "terminate",
+		Short: "Terminate (delete) a compute instance",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			preserveBootVolumeFlag, _ := cmd.Flags().GetBool("preserve-boot-volume")
+			confirmFlag, _ := cmd.Flags().GetBool("confirm")
+			waitFlag, _ := cmd.Flags().GetBool("wait")
+			waitTimeoutFlag, _ := cmd.Flags().GetInt("wait-timeout")
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating compute client: %w", err)
+			}
+
+			instanceID, err := resolveInstanceSelector(cmd, configProvider, computeClient)
+			if err != nil {
+				return err
+			}
+
+			current, err := getInstance(computeClient, core.GetInstanceRequest{InstanceId: &instanceID})
+			if err != nil {
+				return fmt.Errorf("getting instance: %w", err)
+			}
+			if current.Instance.LifecycleState == core.InstanceLifecycleStateTerminated {
+				return fmt.Errorf("instance %s is already TERMINATED", instanceID)
+			}
+
+			if !confirmFlag {
+				message := fmt.Sprintf("Terminate instance '%s' (%s)?", stringValue(current.Instance.DisplayName), instanceID)
+				if !preserveBootVolumeFlag {
+					message = fmt.Sprintf("Terminate instance '%s' (%s) and delete its boot volume?", stringValue(current.Instance.DisplayName), instanceID)
+				}
+				if !confirmPrompt(message) {
+					return errors.New("aborted")
+				}
+			}
+
+			_, err = apiCall(computeClient.TerminateInstance, core.TerminateInstanceRequest{
+				InstanceId:         &instanceID,
+				PreserveBootVolume: common.Bool(preserveBootVolumeFlag),
+			})
+			if err != nil {
+				return fmt.Errorf("terminating instance: %w", err)
+			}
+
+			response, err := getInstance(computeClient, core.GetInstanceRequest{InstanceId: &instanceID})
+			if err != nil {
+				return fmt.Errorf("getting instance: %w", err)
+			}
+			fmt.Printf("Termination initiated for instance %s.\nState: %s\n", instanceID, response.Instance.LifecycleState)
+
+			if !waitFlag {
+				return nil
+			}
+			fmt.Println("Waiting for the instance to be TERMINATED...")
+			timeout := time.Duration(waitTimeoutFlag) * time.Second
+			// TerminateInstance does not return its work request ID, so look it up by resource
+			workRequestClient, err := workrequests.NewWorkRequestClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating work request client: %w", err)
+			}
+			workRequestID, err := latestWorkRequestForResource(workRequestClient, stringValue(current.Instance.CompartmentId), instanceID)
+			if err != nil {
+				return err
+			}
+			if workRequestID != "" {
+				fmt.Printf("Work Request ID: %s\n", workRequestID)
+				if err := waitForWorkRequest(workRequestClient, workRequestID, timeout); err != nil {
+					return fmt.Errorf("instance termination did not succeed: %w", err)
+				}
+			} else if _, err := waitForInstanceState(computeClient, instanceID, core.InstanceLifecycleStateTerminated, timeout); err != nil {
+				return fmt.Errorf("instance was not TERMINATED: %w", err)
+			}
+			fmt.Printf("Instance %s terminated.\n", instanceID)
+			return nil
+		},
+	}
+
+	addInstanceSelectorFlags(terminateCmd, "terminate")
+	terminateCmd.Flags().Bool("preserve-boot-volume", true, "Keep the boot volume after the instance is terminated (use --preserve-boot-volume=false to delete it)")
+	terminateCmd.Flags().Bool("confirm", false, "(Optional) Skip the interactive confirmation prompt")
+	terminateCmd.Flags().Bool("wait", false, "(Optional) Wait for the termination work request to finish and report its outcome")
+	terminateCmd.Flags().Int("wait-timeout", 600, "(Optional) Maximum number of seconds to wait with --wait")
+
+	return terminateCmd
+}
+
+// addInstanceSelectorFlags registers the --id, --name and --compartment-id flags used to
+// select a single instance; see resolveInstanceSelector.
+func addInstanceSelectorFlags(cmd *cobra.Command, verb string) {
+	cmd.Flags().String("id", "", fmt.Sprintf("The OCID of the instance to %s", verb))
+	cmd.Flags().String("name", "", fmt.Sprintf("The display name of the instance to %s", verb))
+	cmd.Flags().String("compartment-id", "", "The OCID, friendly name or path (e.g. 'root/dev/team-a') of the compartment used with --name (optional, defaults to tenancy if not specified)")
+}
+
+// resolveInstanceSelector returns the OCID of the instance selected with either --id or --name
+// (looked up in --compartment-id, or the tenancy root).
+func resolveInstanceSelector(cmd *cobra.Command, configProvider common.ConfigurationProvider, client core.ComputeClient) (string, error) {
+	idFlag, _ := cmd.Flags().GetString("id")
+	nameFlag, _ := cmd.Flags().GetString("name")
+	compartmentFlag, _ := cmd.Flags().GetString("compartment-id")
+
+	if idFlag != "" && nameFlag != "" {
+		return "", fmt.Errorf("specify either --id or --name, not both")
+	}
+	if idFlag != "" {
+		return idFlag, nil
+	}
+	if nameFlag == "" {
+		return "", fmt.Errorf("specify either --id or --name")
+	}
+
+	var compartmentID string
+	var err error
+	if compartmentFlag == "" {
+		compartmentID, err = configProvider.TenancyOCID()
+		if err != nil {
+			return "", fmt.Errorf("failed to get tenancy OCID: %w", err)
+		}
+	} else {
+		compartmentID, err = resolveCompartmentID(compartmentFlag, configProvider)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve compartment: %w", err)
+		}
+	}
+	return resolveInstanceNameToID(nameFlag, compartmentID, client)
+}
+
+func newRunScheduledCmd() *cobra.Command {
+	var runScheduledCmd = &cobra.Command{
+		Use:   "run-scheduled",
+		Short: "Perform scheduled instance actions whose time has passed",
+		Long: `Scans a compartment for instances tagged with ` + autoStopTag + ` and stops every running
+instance whose scheduled time has passed. The tag is removed once the action is issued.
+With --recursive all sub-compartments are scanned as well.
+
+An instance that is not running when its time has passed, e.g. because it was stopped by
+hand, has its tag cleared without any action, so starting it again later does not get it
+stopped by the next run. Terminated instances are ignored.
+
+This command is meant to be invoked periodically, e.g. from cron.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			compartmentInput, _ := cmd.Flags().GetString("compartment-id")
+			recursiveFlag, _ := cmd.Flags().GetBool("recursive")
+			concurrencyFlag, _ := cmd.Flags().GetInt("concurrency")
+			dryRunFlag, _ := cmd.Flags().GetBool("dry-run")
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+			tenancyOCID, err := configProvider.TenancyOCID()
+			if err != nil {
+				return fmt.Errorf("getting tenancy OCID: %w", err)
+			}
+
+			compartmentID := tenancyOCID
+			if compartmentInput != "" {
+				compartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
+				if err != nil {
+					return fmt.Errorf("resolving compartment ID '%s': %w", compartmentInput, err)
+				}
+			}
+
+			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating compute client: %w", err)
+			}
+
+			compartmentIDs := []string{compartmentID}
+			compartmentNames := map[string]string{}
+			if recursiveFlag {
+				identityClient, err := identity.NewIdentityClientWithConfigurationProvider(configProvider)
+				if err != nil {
+					return fmt.Errorf("creating identity client: %w", err)
+				}
+				compartments, err := listSubtreeCompartments(identityClient, tenancyOCID)
+				if err != nil {
+					return fmt.Errorf("listing compartments: %w", err)
+				}
+				for _, compartment := range compartments {
+					compartmentNames[*compartment.Id] = stringValue(compartment.Name)
+				}
+				compartmentIDs = append(compartmentIDs, descendantCompartmentIDs(compartments, compartmentID)...)
+			}
+
+			request := core.ListInstancesRequest{Limit: common.Int(pageSize)}
+			instances, _, err := listInstancesInCompartments(computeClient, request, compartmentIDs, compartmentNames, 0, 0, concurrencyFlag)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			performed, cleared := 0, 0
+			var failed []string
+			for _, instance := range instances {
+				value, ok := instance.FreeformTags[autoStopTag]
+				if !ok {
+					continue
+				}
+				stopAt, err := time.Parse(time.RFC3339, value)
+				if err != nil {
+					fmt.Printf("Warning: Instance %s has an invalid %s tag '%s', skipping.\n", *instance.DisplayName, autoStopTag, value)
+					continue
+				}
+				if stopAt.After(now) {
+					continue
+				}
+
+				if instance.LifecycleState == core.InstanceLifecycleStateTerminating || instance.LifecycleState == core.InstanceLifecycleStateTerminated {
+					continue
+				}
+				if instance.LifecycleState != core.InstanceLifecycleStateRunning {
+					// The schedule is stale: clear it so that a later start is not undone
+					if dryRunFlag {
+						fmt.Printf("Would clear the %s tag of %s instance %s (%s), scheduled for %s.\n", autoStopTag, instance.LifecycleState, *instance.DisplayName, *instance.Id, value)
+						continue
+					}
+					if err := removeInstanceFreeformTag(computeClient, *instance.Id, autoStopTag); err != nil {
+						fmt.Printf("Warning: Could not remove %s tag from instance %s: %v\n", autoStopTag, *instance.Id, err)
+						continue
+					}
+					fmt.Printf("Cleared the %s tag of %s instance %s (%s), scheduled for %s.\n", autoStopTag, instance.LifecycleState, *instance.DisplayName, *instance.Id, value)
+					cleared++
+					continue
+				}
+
+				if dryRunFlag {
+					fmt.Printf("Would stop instance %s (%s), scheduled for %s.\n", *instance.DisplayName, *instance.Id, value)
+					continue
+				}
+
+				fmt.Printf("Stopping instance %s (%s), scheduled for %s...\n", *instance.DisplayName, *instance.Id, value)
+				_, err = apiCall(computeClient.InstanceAction, core.InstanceActionRequest{
+					InstanceId: instance.Id,
+					Action:     core.InstanceActionActionSoftstop,
+				})
+				if err != nil {
+					fmt.Printf("Error: Stopping instance %s failed: %v\n", *instance.Id, err)
+					failed = append(failed, *instance.Id)
+					continue
+				}
+				if err := removeInstanceFreeformTag(computeClient, *instance.Id, autoStopTag); err != nil {
+					fmt.Printf("Warning: Could not remove %s tag from instance %s: %v\n", autoStopTag, *instance.Id, err)
+				}
+				performed++
+			}
+
+			if !dryRunFlag {
+				fmt.Printf("Performed %d scheduled action(s) and cleared %d stale schedule(s).\n", performed, cleared)
+			}
+			// Fail the run so that cron and other schedulers notice; the tags of the failed
+			// instances are kept, so the next run retries them
+			if len(failed) > 0 {
+				return fmt.Errorf("%d scheduled action(s) failed: %s", len(failed), strings.Join(failed, ", "))
+			}
+			return nil
+		},
+	}
+
+	runScheduledCmd.Flags().String("compartment-id", "", "(Optional) OCID, name or path of the compartment to scan (defaults to tenancy root)")
+	runScheduledCmd.Flags().Bool("recursive", false, "(Optional) Also scan all sub-compartments")
+	runScheduledCmd.Flags().Int("concurrency", 8, "(Optional) Maximum number of compartments scanned in parallel with --recursive")
+	runScheduledCmd.Flags().Bool("dry-run", false, "(Optional) Only report the actions that would be performed")
+
+	return runScheduledCmd
+}
+
+// parseScheduleTime accepts either an absolute RFC3339 time or a delay relative to now.
+func parseScheduleTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	delay, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid schedule '%s' (use an RFC3339 time like 2024-01-02T15:04:05Z or a delay like 2h30m)", value)
+	}
+	if delay <= 0 {
+		return time.Time{}, fmt.Errorf("invalid schedule '%s': delay must be positive", value)
+	}
+	return now.Add(delay).UTC().Truncate(time.Second), nil
+}
+
+// setInstanceFreeformTag sets one freeform tag on an instance, keeping its other tags.
+func setInstanceFreeformTag(client core.ComputeClient, instanceID, key, value string) error {
+	response, err := getInstance(client, core.GetInstanceRequest{InstanceId: &instanceID})
+	if err != nil {
+		return fmt.Errorf("failed to get instance: %w", err)
+	}
+	tags := map[string]string{}
+	for k, v := range response.Instance.FreeformTags {
+		tags[k] = v
+	}
+	tags[key] = value
+	return updateInstanceFreeformTags(client, instanceID, tags)
+}
+
+// removeInstanceFreeformTag deletes one freeform tag from an instance, keeping its other tags.
+func removeInstanceFreeformTag(client core.ComputeClient, instanceID, key string) error {
+	response, err := getInstance(client, core.GetInstanceRequest{InstanceId: &instanceID})
+	if err != nil {
+		return fmt.Errorf("failed to get instance: %w", err)
+	}
+	tags := map[string]string{}
+	for k, v := range response.Instance.FreeformTags {
+		if k != key {
+			tags[k] = v
+		}
+	}
+	return updateInstanceFreeformTags(client, instanceID, tags)
+}
+
+func updateInstanceFreeformTags(client core.ComputeClient, instanceID string, tags map[string]string) error {
+	_, err := apiCall(client.UpdateInstance, core.UpdateInstanceRequest{
+		InstanceId: &instanceID,
+		UpdateInstanceDetails: core.UpdateInstanceDetails{
+			FreeformTags: tags,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update instance tags: %w", err)
+	}
+	return nil
+}
+
+func newRebootCmd() *cobra.Command {
+	var rebootCmd = &cobra.Command{
+		Use:   "reboot",
+		Short: "Reboot a compute instance and wait for it to be RUNNING again",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			actionFlag, _ := cmd.Flags().GetString("action")
+			noWaitFlag, _ := cmd.Flags().GetBool("no-wait")
+			waitTimeoutFlag, _ := cmd.Flags().GetInt("wait-timeout")
+			failIfNotRecoveredFlag, _ := cmd.Flags().GetBool("fail-if-not-recovered")
+
+			action, ok := core.GetMappingInstanceActionActionEnum(actionFlag)
+			if !ok || (action != core.InstanceActionActionSoftreset && action != core.InstanceActionActionReset) {
+				return fmt.Errorf("invalid --action '%s' (must be SOFTRESET or RESET)", actionFlag)
+			}
+			if failIfNotRecoveredFlag && noWaitFlag {
+				return errors.New("--fail-if-not-recovered cannot be combined with --no-wait")
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating compute client: %w", err)
+			}
+
+			instanceID, err := resolveInstanceSelector(cmd, configProvider, computeClient)
+			if err != nil {
+				return err
+			}
+
+			response, err := apiCall(computeClient.InstanceAction, core.InstanceActionRequest{
+				InstanceId: &instanceID,
+				Action:     action,
+			})
+			if err != nil {
+				return fmt.Errorf("rebooting instance: %w", err)
+			}
+			fmt.Printf("%s initiated for instance %s.\nState: %s\n", action, instanceID, response.Instance.LifecycleState)
+			if noWaitFlag {
+				return nil
+			}
+
+			fmt.Print("Waiting for instance to return to RUNNING")
+			instance, err := waitForReboot(computeClient, instanceID, time.Duration(waitTimeoutFlag)*time.Second)
+			fmt.Println()
+			if err != nil {
+				fmt.Printf("Instance did not recover: %v\n", err)
+				lastState := string(instance.LifecycleState)
+				if lastState == "" {
+					lastState = "unknown (the instance could not be fetched)"
+				}
+				fmt.Printf("Last state: %s\n", lastState)
+				fmt.Printf("Inspect the serial console with: instances get-console-history --id %s\n", instanceID)
+				if failIfNotRecoveredFlag {
+					return fmt.Errorf("instance did not recover: %w", err)
+				}
+				return nil
+			}
+			fmt.Printf("Instance is %s.\n", instance.LifecycleState)
+			return nil
+		},
+	}
+
+	addInstanceSelectorFlags(rebootCmd, "reboot")
+	rebootCmd.Flags().String("action", "SOFTRESET", "Reboot action: SOFTRESET (graceful) or RESET (hard)")
+	rebootCmd.Flags().Bool("no-wait", false, "(Optional) Return right after initiating the reboot instead of waiting for RUNNING")
+	rebootCmd.Flags().Int("wait-timeout", 300, "(Optional) Maximum number of seconds to wait for the instance to be RUNNING again")
+	rebootCmd.Flags().Bool("fail-if-not-recovered", false, "(Optional) Exit non-zero if the instance is not RUNNING again within --wait-timeout")
+
+	return rebootCmd
+}
+
+func newUpdateCmd() *cobra.Command {
+	var updateCmd = &cobra.Command{
+		Use:   "update",
+		Short: "Resize the OCPUs and/or memory of a Flex shape instance",
+		Long: `Changes the OCPU count and/or memory of an instance that uses a Flex shape.
+
+Resizing a running instance reboots it. With --wait the command returns once the new shape
+config is in effect and the instance has settled again.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ocpusFlag, _ := cmd.Flags().GetFloat32("ocpus")
+			memoryInGBsFlag, _ := cmd.Flags().GetFloat32("memory-in-gbs")
+			waitFlag, _ := cmd.Flags().GetBool("wait")
+			waitTimeoutFlag, _ := cmd.Flags().GetInt("wait-timeout")
+
+			if ocpusFlag < 0 || memoryInGBsFlag < 0 {
+				return errors.New("--ocpus and --memory-in-gbs must be positive")
+			}
+			if ocpusFlag == 0 && memoryInGBsFlag == 0 {
+				return errors.New("specify --ocpus and/or --memory-in-gbs")
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating compute client: %w", err)
+			}
+
+			instanceID, err := resolveInstanceSelector(cmd, configProvider, computeClient)
+			if err != nil {
+				return err
+			}
+
+			current, err := getInstance(computeClient, core.GetInstanceRequest{InstanceId: &instanceID})
+			if err != nil {
+				return fmt.Errorf("getting instance: %w", err)
+			}
+			shape := stringValue(current.Instance.Shape)
+			if !strings.HasSuffix(shape, ".Flex") {
+				return fmt.Errorf("instance %s uses shape %s; only Flex shapes can be resized", instanceID, shape)
+			}
+
+			shapeConfig := core.UpdateInstanceShapeConfigDetails{}
+			if ocpusFlag > 0 {
+				shapeConfig.Ocpus = common.Float32(ocpusFlag)
+			}
+			if memoryInGBsFlag > 0 {
+				shapeConfig.MemoryInGBs = common.Float32(memoryInGBsFlag)
+			}
+
+			response, err := apiCall(computeClient.UpdateInstance, core.UpdateInstanceRequest{
+				InstanceId: &instanceID,
+				UpdateInstanceDetails: core.UpdateInstanceDetails{
+					ShapeConfig: &shapeConfig,
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("updating instance: %w", err)
+			}
+			fmt.Printf("Update initiated for instance %s.\nState: %s\n", instanceID, response.Instance.LifecycleState)
+
+			instance := response.Instance
+			if waitFlag {
+				fmt.Print("Waiting for the new shape config to take effect")
+				instance, err = waitForShapeConfig(computeClient, instanceID, shapeConfig, time.Duration(waitTimeoutFlag)*time.Second)
+				fmt.Println()
+				if err != nil {
+					return fmt.Errorf("waiting for update: %w", err)
+				}
+				fmt.Printf("Instance is %s.\n", instance.LifecycleState)
+			}
+			printShapeConfig(instance)
+			return nil
+		},
+	}
+
+	addInstanceSelectorFlags(updateCmd, "update")
+	updateCmd.Flags().Float32("ocpus", 0, "(Optional) New number of OCPUs")
+	updateCmd.Flags().Float32("memory-in-gbs", 0, "(Optional) New amount of memory in GB")
+	updateCmd.Flags().Bool("wait", false, "(Optional) Wait until the new shape config is in effect")
+	updateCmd.Flags().Int("wait-timeout", 600, "(Optional) Maximum number of seconds to wait with --wait")
+
+	return updateCmd
+}
+
+func newRenameCmd() *cobra.Command {
+	var renameCmd = &cobra.Command{
+		Use:   "rename",
+		Short: "Change the display name of a compute instance",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newNameFlag, _ := cmd.Flags().GetString("new-name")
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating compute client: %w", err)
+			}
+
+			instanceID, err := resolveInstanceSelector(cmd, configProvider, computeClient)
+			if err != nil {
+				return err
+			}
+
+			response, err := apiCall(computeClient.UpdateInstance, core.UpdateInstanceRequest{
+				InstanceId: &instanceID,
+				UpdateInstanceDetails: core.UpdateInstanceDetails{
+					DisplayName: &newNameFlag,
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("renaming instance: %w", err)
+			}
+			fmt.Printf("Instance %s renamed to '%s'.\n", instanceID, stringValue(response.Instance.DisplayName))
+			return nil
+		},
+	}
+
+	addInstanceSelectorFlags(renameCmd, "rename")
+	renameCmd.Flags().String("new-name", "", "New display name for the instance (Required)")
+	_ = renameCmd.MarkFlagRequired("new-name")
+
+	return renameCmd
+}
+
+// waitForShapeConfig polls an instance until its shape config matches the requested values and
+// it is in a settled state. A progress dot is printed for every poll.
+func waitForShapeConfig(client core.ComputeClient, instanceID string, want core.UpdateInstanceShapeConfigDetails, timeout time.Duration) (core.Instance, error) {
+	start := time.Now()
+	for {
+		response, err := getInstance(client, core.GetInstanceRequest{InstanceId: &instanceID})
+		if err != nil {
+			return core.Instance{}, fmt.Errorf("failed to get instance: %w", err)
+		}
+		instance := response.Instance
+		if instance.LifecycleState == core.InstanceLifecycleStateTerminating || instance.LifecycleState == core.InstanceLifecycleStateTerminated {
+			return instance, fmt.Errorf("instance entered terminal state %s", instance.LifecycleState)
+		}
+		if isSettledInstanceState(instance.LifecycleState) && shapeConfigMatches(instance.ShapeConfig, want) {
+			return instance, nil
+		}
+		if time.Since(start) > timeout {
+			return instance, fmt.Errorf("timed out after %s (last state: %s)", timeout, instance.LifecycleState)
+		}
+		fmt.Print(".")
+		pause(5 * time.Second)
+	}
+}
+
+// shapeConfigMatches reports whether an instance's shape config has the requested values.
+func shapeConfigMatches(config *core.InstanceShapeConfig, want core.UpdateInstanceShapeConfigDetails) bool {
+	if config == nil {
+		return false
+	}
+	if want.Ocpus != nil && (config.Ocpus == nil || *config.Ocpus != *want.Ocpus) {
+		return false
+	}
+	if want.MemoryInGBs != nil && (config.MemoryInGBs == nil || *config.MemoryInGBs != *want.MemoryInGBs) {
+		return false
+	}
+	return true
+}
+
+// printShapeConfig prints the shape and OCPU/memory configuration of an instance.
+func printShapeConfig(instance core.Instance) {
+	fmt.Printf("Shape: %s\n", stringValue(instance.Shape))
+	if config := instance.ShapeConfig; config != nil {
+		if config.Ocpus != nil {
+			fmt.Printf("OCPUs: %.1f\n", *config.Ocpus)
+		}
+		if config.MemoryInGBs != nil {
+			fmt.Printf("Memory (GB): %.1f\n", *config.MemoryInGBs)
+		}
+	}
+}
+
+// waitForReboot waits for a rebooting instance to come back to RUNNING. Because a reboot may
+// not have left RUNNING yet when polling starts, RUNNING only counts once the instance was seen
+// in another state or after a short grace period. A progress dot is printed for every poll. The
+// last successfully fetched instance is returned, also on error.
+func waitForReboot(client core.ComputeClient, instanceID string, timeout time.Duration) (core.Instance, error) {
+	const gracePeriod = 30 * time.Second
+
+	start := time.Now()
+	transitioned := false
+	// The last successfully fetched instance is returned on every path, so errors can report it
+	var instance core.Instance
+	for {
+		response, err := getInstance(client, core.GetInstanceRequest{InstanceId: &instanceID})
+		if err != nil {
+			return instance, fmt.Errorf("failed to get instance: %w", err)
+		}
+		instance = response.Instance
+		if instance.LifecycleState != core.InstanceLifecycleStateRunning {
+			transitioned = true
+		} else if transitioned || time.Since(start) > gracePeriod {
+			return instance, nil
+		}
+		if instance.LifecycleState == core.InstanceLifecycleStateTerminating || instance.LifecycleState == core.InstanceLifecycleStateTerminated {
+			return instance, fmt.Errorf("instance entered terminal state %s", instance.LifecycleState)
+		}
+		if time.Since(start) > timeout {
+			return instance, fmt.Errorf("timed out after %s", timeout)
+		}
+		fmt.Print(".")
+		pause(5 * time.Second)
+	}
+}
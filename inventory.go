@@ -0,0 +1,327 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/spf13/cobra"
+)
+
+// inventoryJSON is the document written by `instances export-inventory`.
+type inventoryJSON struct {
+	GeneratedAt    string                  `json:"generatedAt"`
+	Region         string                  `json:"region"`
+	CompartmentID  string                  `json:"compartmentId"`
+	CompartmentIDs []string                `json:"compartmentIds"`
+	Instances      []inventoryInstanceJSON `json:"instances"`
+	Subnets        []subnetJSON            `json:"subnets"`
+	Vcns           []vcnJSON               `json:"vcns"`
+}
+
+// inventoryInstanceJSON is an instance with its shape config, tags, VNICs and attached volumes.
+type inventoryInstanceJSON struct {
+	instanceJSON
+	ShapeConfig  *core.InstanceShapeConfig         `json:"shapeConfig,omitempty"`
+	FreeformTags map[string]string                 `json:"freeformTags,omitempty"`
+	DefinedTags  map[string]map[string]interface{} `json:"definedTags,omitempty"`
+	Vnics        []vnicJSON                        `json:"vnics"`
+	Volumes      []inventoryVolumeJSON             `json:"volumes"`
+}
+
+// inventoryVolumeJSON is a boot or block volume attached to an instance.
+type inventoryVolumeJSON struct {
+	AttachmentID   string `json:"attachmentId"`
+	VolumeID       string `json:"volumeId"`
+	DisplayName    string `json:"displayName"`
+	Boot           bool   `json:"boot"`
+	AttachmentType string `json:"attachmentType,omitempty"`
+	SizeInGBs      int64  `json:"sizeInGBs,omitempty"`
+	LifecycleState string `json:"lifecycleState"`
+}
+
+func newExportInventoryCmd() *cobra.Command {
+	var exportInventoryCmd = &cobra.Command{
+		Use:   "export-inventory",
+		Short: "Export instances with their IPs, volumes, subnets and VCNs as one JSON document",
+		Long: `Gathers every non-terminated instance in a compartment (and, with --recursive, all of
+its sub-compartments) together with its shape config, tags, VNICs and attached boot/block
+volumes, plus the subnets and VCNs those VNICs live in, and writes it as a single JSON
+document to --out (stdout if omitted).`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// 1. Get Flags
+			compartmentInput, _ := cmd.Flags().GetString("compartment-id")
+			recursiveFlag, _ := cmd.Flags().GetBool("recursive")
+			outFlag, _ := cmd.Flags().GetString("out")
+			concurrencyFlag, _ := cmd.Flags().GetInt("concurrency")
+
+			// 2. Setup Config Provider and Clients
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+			tenancyOCID, err := configProvider.TenancyOCID()
+			if err != nil {
+				return fmt.Errorf("getting tenancy OCID: %w", err)
+			}
+			region, _ := configProvider.Region()
+
+			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating compute client: %w", err)
+			}
+			networkClient, err := newVirtualNetworkClient(configProvider)
+			if err != nil {
+				return err
+			}
+			blockstorageClient, err := core.NewBlockstorageClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating block storage client: %w", err)
+			}
+
+			// 3. Resolve Compartments
+			compartmentID := tenancyOCID
+			if compartmentInput != "" {
+				compartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
+				if err != nil {
+					return fmt.Errorf("resolving compartment: %w", err)
+				}
+			}
+			compartmentIDs := []string{compartmentID}
+			compartmentNames := map[string]string{}
+			if recursiveFlag {
+				identityClient, err := identity.NewIdentityClientWithConfigurationProvider(configProvider)
+				if err != nil {
+					return fmt.Errorf("creating identity client: %w", err)
+				}
+				compartments, err := listSubtreeCompartments(identityClient, tenancyOCID)
+				if err != nil {
+					return fmt.Errorf("listing compartments: %w", err)
+				}
+				for _, compartment := range compartments {
+					compartmentNames[*compartment.Id] = stringValue(compartment.Name)
+				}
+				compartmentIDs = append(compartmentIDs, descendantCompartmentIDs(compartments, compartmentID)...)
+			}
+
+			// 4. List Instances
+			request := core.ListInstancesRequest{Limit: common.Int(pageSize)}
+			items, _, err := listInstancesInCompartments(computeClient, request, compartmentIDs, compartmentNames, 0, 0, concurrencyFlag)
+			if err != nil {
+				return err
+			}
+			var instances []core.Instance
+			for _, instance := range items {
+				if instance.LifecycleState != core.InstanceLifecycleStateTerminated {
+					instances = append(instances, instance)
+				}
+			}
+			fmt.Fprintf(os.Stderr, "Gathering details of %d instances in %d compartments...\n", len(instances), len(compartmentIDs))
+
+			// 5. Gather VNICs and Volumes per Instance
+			records := make([]inventoryInstanceJSON, len(instances))
+			err = runConcurrently(len(instances), concurrencyFlag, func(i int) error {
+				record, err := gatherInstanceInventory(computeClient, networkClient, blockstorageClient, instances[i])
+				records[i] = record
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("gathering inventory: %w", err)
+			}
+
+			// 6. Gather Subnets and VCNs
+			subnets, vcns, err := gatherNetworkInventory(networkClient, records, concurrencyFlag)
+			if err != nil {
+				return fmt.Errorf("gathering network inventory: %w", err)
+			}
+
+			inventory := inventoryJSON{
+				GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
+				Region:         region,
+				CompartmentID:  compartmentID,
+				CompartmentIDs: compartmentIDs,
+				Instances:      records,
+				Subnets:        subnets,
+				Vcns:           vcns,
+			}
+
+			// 7. Write Document
+			if outFlag == "" {
+				if err := printJSON(inventory); err != nil {
+					return fmt.Errorf("encoding JSON: %w", err)
+				}
+				return nil
+			}
+			data, err := json.MarshalIndent(inventory, "", "  ")
+			if err != nil {
+				return fmt.Errorf("encoding JSON: %w", err)
+			}
+			if err := os.WriteFile(outFlag, append(data, '\n'), 0644); err != nil {
+				return fmt.Errorf("writing inventory to '%s': %w", outFlag, err)
+			}
+			fmt.Printf("Wrote inventory of %d instances, %d subnets and %d VCNs to %s\n", len(records), len(subnets), len(vcns), outFlag)
+			return nil
+		},
+	}
+
+	exportInventoryCmd.Flags().String("compartment-id", "", "(Optional) OCID, name or path of the compartment to export (defaults to tenancy root)")
+	exportInventoryCmd.Flags().Bool("recursive", false, "(Optional) Include all sub-compartments")
+	exportInventoryCmd.Flags().String("out", "", "(Optional) File to write the inventory JSON to (stdout if omitted)")
+	exportInventoryCmd.Flags().Int("concurrency", 8, "(Optional) Maximum number of parallel lookups")
+
+	return exportInventoryCmd
+}
+
+// gatherInstanceInventory collects the VNICs and attached boot/block volumes of one instance.
+func gatherInstanceInventory(computeClient core.ComputeClient, networkClient core.VirtualNetworkClient, blockstorageClient core.BlockstorageClient, instance core.Instance) (inventoryInstanceJSON, error) {
+	record := inventoryInstanceJSON{
+		instanceJSON: newInstanceJSON(&instance),
+		ShapeConfig:  instance.ShapeConfig,
+		FreeformTags: instance.FreeformTags,
+		DefinedTags:  instance.DefinedTags,
+		Volumes:      []inventoryVolumeJSON{},
+	}
+
+	vnics, err := resolveInstanceVnics(computeClient, networkClient, *instance.CompartmentId, *instance.Id)
+	if err != nil {
+		return record, err
+	}
+	record.Vnics = vnics
+
+	bootRequest := core.ListBootVolumeAttachmentsRequest{
+		AvailabilityDomain: instance.AvailabilityDomain,
+		CompartmentId:      instance.CompartmentId,
+		InstanceId:         instance.Id,
+	}
+	bootAttachments, _, err := collectPages(0, func(page *string) ([]core.BootVolumeAttachment, *string, error) {
+		bootRequest.Page = page
+		response, err := apiCall(computeClient.ListBootVolumeAttachments, bootRequest)
+		return response.Items, response.OpcNextPage, err
+	})
+	if err != nil {
+		return record, fmt.Errorf("failed to list boot volume attachments of instance %s: %w", *instance.Id, err)
+	}
+	for _, attachment := range bootAttachments {
+		if attachment.LifecycleState != core.BootVolumeAttachmentLifecycleStateAttached {
+			continue
+		}
+		volume := inventoryVolumeJSON{
+			AttachmentID:   stringValue(attachment.Id),
+			VolumeID:       stringValue(attachment.BootVolumeId),
+			Boot:           true,
+			LifecycleState: string(attachment.LifecycleState),
+		}
+		response, err := apiCall(blockstorageClient.GetBootVolume, core.GetBootVolumeRequest{BootVolumeId: attachment.BootVolumeId})
+		if err != nil {
+			return record, fmt.Errorf("failed to get boot volume %s: %w", volume.VolumeID, err)
+		}
+		volume.DisplayName = stringValue(response.DisplayName)
+		if response.SizeInGBs != nil {
+			volume.SizeInGBs = *response.SizeInGBs
+		}
+		record.Volumes = append(record.Volumes, volume)
+	}
+
+	volumeRequest := core.ListVolumeAttachmentsRequest{
+		CompartmentId: instance.CompartmentId,
+		InstanceId:    instance.Id,
+	}
+	volumeAttachments, _, err := collectPages(0, func(page *string) ([]core.VolumeAttachment, *string, error) {
+		volumeRequest.Page = page
+		response, err := apiCall(computeClient.ListVolumeAttachments, volumeRequest)
+		return response.Items, response.OpcNextPage, err
+	})
+	if err != nil {
+		return record, fmt.Errorf("failed to list volume attachments of instance %s: %w", *instance.Id, err)
+	}
+	for _, attachment := range volumeAttachments {
+		if attachment.GetLifecycleState() != core.VolumeAttachmentLifecycleStateAttached {
+			continue
+		}
+		volume := inventoryVolumeJSON{
+			AttachmentID:   stringValue(attachment.GetId()),
+			VolumeID:       stringValue(attachment.GetVolumeId()),
+			LifecycleState: string(attachment.GetLifecycleState()),
+		}
+		switch attachment.(type) {
+		case core.IScsiVolumeAttachment:
+			volume.AttachmentType = "iscsi"
+		case core.ParavirtualizedVolumeAttachment:
+			volume.AttachmentType = "paravirtualized"
+		}
+		response, err := apiCall(blockstorageClient.GetVolume, core.GetVolumeRequest{VolumeId: attachment.GetVolumeId()})
+		if err != nil {
+			return record, fmt.Errorf("failed to get volume %s: %w", volume.VolumeID, err)
+		}
+		volume.DisplayName = stringValue(response.DisplayName)
+		if response.SizeInGBs != nil {
+			volume.SizeInGBs = *response.SizeInGBs
+		}
+		record.Volumes = append(record.Volumes, volume)
+	}
+
+	return record, nil
+}
+
+// gatherNetworkInventory fetches the subnets referenced by the instances' VNICs and the VCNs
+// those subnets belong to. Both results are sorted by ID.
+func gatherNetworkInventory(client core.VirtualNetworkClient, records []inventoryInstanceJSON, concurrency int) ([]subnetJSON, []vcnJSON, error) {
+	subnetIDSet := map[string]bool{}
+	for _, record := range records {
+		for _, vnic := range record.Vnics {
+			if vnic.SubnetID != "" {
+				subnetIDSet[vnic.SubnetID] = true
+			}
+		}
+	}
+	subnetIDs := sortedKeys(subnetIDSet)
+
+	subnets := make([]subnetJSON, len(subnetIDs))
+	err := runConcurrently(len(subnetIDs), concurrency, func(i int) error {
+		response, err := apiCall(client.GetSubnet, core.GetSubnetRequest{SubnetId: &subnetIDs[i]})
+		if err != nil {
+			return fmt.Errorf("failed to get subnet %s: %w", subnetIDs[i], err)
+		}
+		subnets[i] = newSubnetJSON(response.Subnet)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vcnIDSet := map[string]bool{}
+	for _, subnet := range subnets {
+		vcnIDSet[subnet.VcnID] = true
+	}
+	vcnIDs := sortedKeys(vcnIDSet)
+
+	vcns := make([]vcnJSON, len(vcnIDs))
+	err = runConcurrently(len(vcnIDs), concurrency, func(i int) error {
+		response, err := apiCall(client.GetVcn, core.GetVcnRequest{VcnId: &vcnIDs[i]})
+		if err != nil {
+			return fmt.Errorf("failed to get VCN %s: %w", vcnIDs[i], err)
+		}
+		vcns[i] = newVcnJSON(response.Vcn)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return subnets, vcns, nil
+}
+
+// sortedKeys returns the keys of a string set in ascending order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/loadbalancer"
+	"github.com/spf13/cobra"
+)
+
+func newLoadBalancerCmd() *cobra.Command {
+	var lbCmd = &cobra.Command{
+		Use:   "lb",
+		Short: "Manage load balancers",
+	}
+
+	var backendCmd = &cobra.Command{
+		Use:   "backend",
+		Short: "Manage load balancer backends",
+	}
+
+	var addCmd = &cobra.Command{
+		Use:   "add",
+		Short: "Register an instance as a backend in a backend set",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			weightFlag, _ := cmd.Flags().GetInt("weight")
+
+			change, err := prepareBackendChange(cmd)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Using private IP %s of instance %s\n", change.ipAddress, change.instanceID)
+
+			details := loadbalancer.CreateBackendDetails{
+				IpAddress: &change.ipAddress,
+				Port:      &change.port,
+			}
+			if weightFlag > 0 {
+				details.Weight = common.Int(weightFlag)
+			}
+
+			response, err := apiCall(change.client.CreateBackend, loadbalancer.CreateBackendRequest{
+				LoadBalancerId:       &change.lbID,
+				BackendSetName:       &change.backendSetName,
+				CreateBackendDetails: details,
+				OpcRetryToken:        common.String(retryToken(cmd)),
+			})
+			if err != nil {
+				return fmt.Errorf("creating backend: %w", err)
+			}
+			fmt.Printf("Backend %s:%d added to backend set '%s'.\nWork Request ID: %s\n", change.ipAddress, change.port, change.backendSetName, stringValue(response.OpcWorkRequestId))
+			return nil
+		},
+	}
+
+	var removeCmd = &cobra.Command{
+		Use:   "remove",
+		Short: "Remove an instance's backend from a backend set",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			change, err := prepareBackendChange(cmd)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Using private IP %s of instance %s\n", change.ipAddress, change.instanceID)
+
+			backendName := fmt.Sprintf("%s:%d", change.ipAddress, change.port)
+			response, err := apiCall(change.client.DeleteBackend, loadbalancer.DeleteBackendRequest{
+				LoadBalancerId: &change.lbID,
+				BackendSetName: &change.backendSetName,
+				BackendName:    &backendName,
+			})
+			if err != nil {
+				return fmt.Errorf("deleting backend: %w", err)
+			}
+			fmt.Printf("Backend %s removed from backend set '%s'.\nWork Request ID: %s\n", backendName, change.backendSetName, stringValue(response.OpcWorkRequestId))
+			return nil
+		},
+	}
+
+	for _, c := range []*cobra.Command{addCmd, removeCmd} {
+		c.Flags().String("lb-id", "", "OCID of the load balancer (Required)")
+		c.Flags().String("backend-set", "", "Name of the backend set (Required)")
+		c.Flags().String("instance-id", "", "OCID of the backend instance")
+		c.Flags().String("name", "", "Display name of the backend instance (alternative to --instance-id)")
+		c.Flags().String("compartment-id", "", "(Optional) OCID, name or path of the compartment used to resolve --name (defaults to tenancy root)")
+		c.Flags().Int("port", 0, "Backend port on the instance (Required)")
+		_ = c.MarkFlagRequired("lb-id")
+		_ = c.MarkFlagRequired("backend-set")
+		_ = c.MarkFlagRequired("port")
+	}
+	addCmd.Flags().Int("weight", 0, "(Optional) Load balancing weight of the backend")
+	addRetryTokenFlag(addCmd)
+
+	backendCmd.AddCommand(addCmd, removeCmd)
+	lbCmd.AddCommand(backendCmd)
+
+	return lbCmd
+}
+
+// backendChange is the resolved target of a backend add/remove.
+type backendChange struct {
+	client         loadbalancer.LoadBalancerClient
+	lbID           string
+	backendSetName string
+	instanceID     string
+	ipAddress      string
+	port           int
+}
+
+// prepareBackendChange resolves the flags shared by backend add/remove: it validates that the
+// backend set exists and resolves the instance's primary private IP.
+func prepareBackendChange(cmd *cobra.Command) (backendChange, error) {
+	lbIDFlag, _ := cmd.Flags().GetString("lb-id")
+	backendSetFlag, _ := cmd.Flags().GetString("backend-set")
+	instanceIDFlag, _ := cmd.Flags().GetString("instance-id")
+	nameFlag, _ := cmd.Flags().GetString("name")
+	compartmentInput, _ := cmd.Flags().GetString("compartment-id")
+	portFlag, _ := cmd.Flags().GetInt("port")
+
+	if (instanceIDFlag == "") == (nameFlag == "") {
+		return backendChange{}, errors.New("specify either --instance-id or --name")
+	}
+	if portFlag <= 0 || portFlag > 65535 {
+		return backendChange{}, fmt.Errorf("invalid --port %d", portFlag)
+	}
+
+	configProvider, err := newConfigProvider(cmd)
+	if err != nil {
+		return backendChange{}, err
+	}
+
+	lbClient, err := loadbalancer.NewLoadBalancerClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return backendChange{}, fmt.Errorf("creating load balancer client: %w", err)
+	}
+	_, err = apiCall(lbClient.GetBackendSet, loadbalancer.GetBackendSetRequest{
+		LoadBalancerId: &lbIDFlag,
+		BackendSetName: &backendSetFlag,
+	})
+	if err != nil {
+		return backendChange{}, fmt.Errorf("backend set '%s' not found on load balancer %s: %w", backendSetFlag, lbIDFlag, err)
+	}
+
+	computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return backendChange{}, fmt.Errorf("creating compute client: %w", err)
+	}
+	networkClient, err := newVirtualNetworkClient(configProvider)
+	if err != nil {
+		return backendChange{}, err
+	}
+
+	instanceID := instanceIDFlag
+	if nameFlag != "" {
+		var compartmentID string
+		if compartmentInput != "" {
+			compartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
+			if err != nil {
+				return backendChange{}, fmt.Errorf("resolving compartment ID '%s': %w", compartmentInput, err)
+			}
+		} else {
+			compartmentID, err = configProvider.TenancyOCID()
+			if err != nil {
+				return backendChange{}, fmt.Errorf("getting tenancy OCID: %w", err)
+			}
+		}
+		instanceID, err = resolveInstanceNameToID(nameFlag, compartmentID, computeClient)
+		if err != nil {
+			return backendChange{}, fmt.Errorf("resolving instance name '%s': %w", nameFlag, err)
+		}
+	}
+
+	instanceResponse, err := getInstance(computeClient, core.GetInstanceRequest{InstanceId: &instanceID})
+	if err != nil {
+		return backendChange{}, fmt.Errorf("getting instance %s: %w", instanceID, err)
+	}
+	vnics, err := resolveInstanceVnics(computeClient, networkClient, *instanceResponse.Instance.CompartmentId, instanceID)
+	if err != nil {
+		return backendChange{}, fmt.Errorf("resolving instance IP: %w", err)
+	}
+	for _, vnic := range vnics {
+		if vnic.Primary && vnic.PrivateIP != "" {
+			return backendChange{
+				client:         lbClient,
+				lbID:           lbIDFlag,
+				backendSetName: backendSetFlag,
+				instanceID:     instanceID,
+				ipAddress:      vnic.PrivateIP,
+				port:           portFlag,
+			}, nil
+		}
+	}
+	return backendChange{}, fmt.Errorf("instance %s has no primary VNIC with a private IP", instanceID)
+}
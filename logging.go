@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// logLevels maps the accepted --log-level values to slog levels.
+var logLevels = map[string]slog.Level{
+	"error": slog.LevelError,
+	"warn":  slog.LevelWarn,
+	"info":  slog.LevelInfo,
+	"debug": slog.LevelDebug,
+}
+
+// applyLogLevel reads the persistent --log-level flag and installs a stderr logger at that
+// level as the slog default.
+func applyLogLevel(cmd *cobra.Command) error {
+	logLevelFlag, _ := cmd.Flags().GetString("log-level")
+	level, ok := logLevels[strings.ToLower(logLevelFlag)]
+	if !ok {
+		return fmt.Errorf("invalid --log-level '%s' (must be error, warn, info or debug)", logLevelFlag)
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+	return nil
+}
@@ -0,0 +1,597 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/spf13/cobra"
+)
+
+func newNetworkCmd() *cobra.Command {
+	var networkCmd = &cobra.Command{
+		Use:   "network",
+		Short: "Manage networking resources",
+	}
+
+	var vnicCmd = &cobra.Command{
+		Use:   "vnic",
+		Short: "Inspect VNICs",
+	}
+
+	var getVnicCmd = &cobra.Command{
+		Use:   "get",
+		Short: "Show the details of a VNIC",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			idFlag, _ := cmd.Flags().GetString("id")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			networkClient, err := newVirtualNetworkClient(configProvider)
+			if err != nil {
+				return err
+			}
+
+			response, err := apiCall(networkClient.GetVnic, core.GetVnicRequest{VnicId: &idFlag})
+			if err != nil {
+				return fmt.Errorf("getting VNIC: %w", err)
+			}
+
+			if isStructuredOutput(output) {
+				if err := printStructured(output, response.Vnic); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			displayVnicDetails(&response.Vnic)
+			return nil
+		},
+	}
+
+	getVnicCmd.Flags().String("id", "", "The OCID of the VNIC (Required)")
+	_ = getVnicCmd.MarkFlagRequired("id")
+
+	var listVnicsCmd = &cobra.Command{
+		Use:   "list-vnics",
+		Short: "List the VNICs attached to an instance with their IPs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			instanceIDFlag, _ := cmd.Flags().GetString("instance-id")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating compute client: %w", err)
+			}
+			networkClient, err := newVirtualNetworkClient(configProvider)
+			if err != nil {
+				return err
+			}
+
+			instance, err := getInstance(computeClient, core.GetInstanceRequest{InstanceId: &instanceIDFlag})
+			if err != nil {
+				return fmt.Errorf("getting instance: %w", err)
+			}
+			vnics, err := resolveInstanceVnics(computeClient, networkClient, *instance.CompartmentId, instanceIDFlag)
+			if err != nil {
+				return fmt.Errorf("resolving VNICs: %w", err)
+			}
+
+			if isStructuredOutput(output) {
+				region, _ := configProvider.Region()
+				if err := printList(output, vnics, listMeta{Count: len(vnics), Region: region}); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			if len(vnics) == 0 {
+				fmt.Println("No VNICs attached.")
+				return nil
+			}
+			for _, vnic := range vnics {
+				fmt.Printf("VNIC ID: %s, Private IP: %s, Public IP: %s, Hostname: %s, Primary: %t\n", vnic.VnicID, vnic.PrivateIP, vnic.PublicIP, vnic.Hostname, vnic.Primary)
+			}
+			return nil
+		},
+	}
+
+	listVnicsCmd.Flags().String("instance-id", "", "The OCID of the instance (Required)")
+	_ = listVnicsCmd.MarkFlagRequired("instance-id")
+
+	var listSubnetsCmd = &cobra.Command{
+		Use:   "list-subnets",
+		Short: "List the subnets of a compartment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			compartmentInput, _ := cmd.Flags().GetString("compartment-id")
+			vcnIDFlag, _ := cmd.Flags().GetString("vcn-id")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			var compartmentID string
+			if compartmentInput == "" {
+				compartmentID, err = configProvider.TenancyOCID()
+				if err != nil {
+					return fmt.Errorf("getting tenancy OCID: %w", err)
+				}
+			} else {
+				compartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
+				if err != nil {
+					return fmt.Errorf("resolving compartment: %w", err)
+				}
+			}
+
+			networkClient, err := newVirtualNetworkClient(configProvider)
+			if err != nil {
+				return err
+			}
+
+			request := core.ListSubnetsRequest{
+				CompartmentId: &compartmentID,
+				Limit:         common.Int(pageSize),
+			}
+			if vcnIDFlag != "" {
+				request.VcnId = &vcnIDFlag
+			}
+			subnets, truncated, err := collectPages(maxItems(cmd), func(page *string) ([]core.Subnet, *string, error) {
+				request.Page = page
+				response, err := apiCall(networkClient.ListSubnets, request)
+				return response.Items, response.OpcNextPage, err
+			})
+			if err != nil {
+				return fmt.Errorf("listing subnets: %w", err)
+			}
+
+			if isStructuredOutput(output) {
+				items := make([]subnetJSON, len(subnets))
+				for i, subnet := range subnets {
+					items[i] = newSubnetJSON(subnet)
+				}
+				region, _ := configProvider.Region()
+				if err := printList(output, items, listMeta{Count: len(items), Truncated: truncated, Region: region}); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			if len(subnets) == 0 {
+				fmt.Println("No subnets found.")
+				return nil
+			}
+			for _, subnet := range subnets {
+				availabilityDomain := stringValue(subnet.AvailabilityDomain)
+				if availabilityDomain == "" {
+					availabilityDomain = "(regional)"
+				}
+				fmt.Printf("Subnet: %s\n", stringValue(subnet.DisplayName))
+				fmt.Printf("  ID: %s\n", stringValue(subnet.Id))
+				fmt.Printf("  CIDR: %s\n", stringValue(subnet.CidrBlock))
+				fmt.Printf("  Availability Domain: %s\n", availabilityDomain)
+				fmt.Printf("  Prohibits Public IP: %t\n", subnet.ProhibitPublicIpOnVnic != nil && *subnet.ProhibitPublicIpOnVnic)
+			}
+			return nil
+		},
+	}
+
+	listSubnetsCmd.Flags().String("compartment-id", "", "(Optional) OCID, name or path of the compartment (defaults to tenancy root)")
+	listSubnetsCmd.Flags().String("vcn-id", "", "(Optional) Only list subnets of this VCN")
+	addMaxItemsFlag(listSubnetsCmd)
+
+	var listVcnsCmd = &cobra.Command{
+		Use:   "list-vcns",
+		Short: "List the VCNs of a compartment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			compartmentInput, _ := cmd.Flags().GetString("compartment-id")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			var compartmentID string
+			if compartmentInput == "" {
+				compartmentID, err = configProvider.TenancyOCID()
+				if err != nil {
+					return fmt.Errorf("getting tenancy OCID: %w", err)
+				}
+			} else {
+				compartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
+				if err != nil {
+					return fmt.Errorf("resolving compartment: %w", err)
+				}
+			}
+
+			networkClient, err := newVirtualNetworkClient(configProvider)
+			if err != nil {
+				return err
+			}
+
+			request := core.ListVcnsRequest{
+				CompartmentId: &compartmentID,
+				Limit:         common.Int(pageSize),
+			}
+			vcns, truncated, err := collectPages(maxItems(cmd), func(page *string) ([]core.Vcn, *string, error) {
+				request.Page = page
+				response, err := apiCall(networkClient.ListVcns, request)
+				return response.Items, response.OpcNextPage, err
+			})
+			if err != nil {
+				return fmt.Errorf("listing VCNs: %w", err)
+			}
+
+			if isStructuredOutput(output) {
+				items := make([]vcnJSON, len(vcns))
+				for i, vcn := range vcns {
+					items[i] = newVcnJSON(vcn)
+				}
+				region, _ := configProvider.Region()
+				if err := printList(output, items, listMeta{Count: len(items), Truncated: truncated, Region: region}); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			if len(vcns) == 0 {
+				fmt.Println("No VCNs found.")
+				return nil
+			}
+			for _, vcn := range vcns {
+				fmt.Printf("VCN: %s\n", stringValue(vcn.DisplayName))
+				fmt.Printf("  ID: %s\n", stringValue(vcn.Id))
+				fmt.Printf("  CIDR Blocks: %s\n", strings.Join(vcn.CidrBlocks, ", "))
+				fmt.Printf("  DNS Label: %s\n", stringValue(vcn.DnsLabel))
+				fmt.Printf("  State: %s\n", vcn.LifecycleState)
+			}
+			return nil
+		},
+	}
+
+	listVcnsCmd.Flags().String("compartment-id", "", "(Optional) OCID, name or path of the compartment (defaults to tenancy root)")
+	addMaxItemsFlag(listVcnsCmd)
+
+	vnicCmd.AddCommand(getVnicCmd)
+	networkCmd.AddCommand(vnicCmd, listVnicsCmd, listVcnsCmd, listSubnetsCmd, newLoadBalancerCmd())
+
+	return networkCmd
+}
+
+// newListVnicAttachmentsCmd builds the command that lists the VNIC attachments of an instance.
+func newListVnicAttachmentsCmd() *cobra.Command {
+	var listVnicAttachmentsCmd = &cobra.Command{
+		Use:   "list-vnic-attachments",
+		Short: "List the VNIC attachments of an instance",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			instanceIDFlag, _ := cmd.Flags().GetString("instance-id")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating compute client: %w", err)
+			}
+
+			instance, err := getInstance(computeClient, core.GetInstanceRequest{InstanceId: &instanceIDFlag})
+			if err != nil {
+				return fmt.Errorf("getting instance: %w", err)
+			}
+
+			request := core.ListVnicAttachmentsRequest{
+				CompartmentId: instance.CompartmentId,
+				InstanceId:    &instanceIDFlag,
+				Limit:         common.Int(pageSize),
+			}
+			attachments, _, err := collectPages(0, func(page *string) ([]core.VnicAttachment, *string, error) {
+				request.Page = page
+				response, err := apiCall(computeClient.ListVnicAttachments, request)
+				return response.Items, response.OpcNextPage, err
+			})
+			if err != nil {
+				return fmt.Errorf("listing VNIC attachments: %w", err)
+			}
+
+			items := make([]vnicAttachmentJSON, len(attachments))
+			for i, attachment := range attachments {
+				items[i] = newVnicAttachmentJSON(attachment)
+			}
+			if isStructuredOutput(output) {
+				region, _ := configProvider.Region()
+				if err := printList(output, items, listMeta{Count: len(items), Region: region}); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			if len(items) == 0 {
+				fmt.Println("No VNIC attachments found.")
+				return nil
+			}
+			table := newTextTable(cmd,
+				tableColumn{header: "ATTACHMENT ID", ocid: true},
+				tableColumn{header: "VNIC ID", ocid: true},
+				tableColumn{header: "NIC INDEX", numeric: true},
+				tableColumn{header: "STATE"},
+			)
+			for _, attachment := range items {
+				vnicID := attachment.VnicID
+				if vnicID == "" {
+					vnicID = "-" // not yet known while the attachment is ATTACHING
+				}
+				table.addRow(attachment.ID, vnicID, fmt.Sprint(attachment.NicIndex), attachment.LifecycleState)
+			}
+			table.print()
+			return nil
+		},
+	}
+
+	listVnicAttachmentsCmd.Flags().String("instance-id", "", "The OCID of the instance (Required)")
+	addNoTruncateFlag(listVnicAttachmentsCmd)
+	_ = listVnicAttachmentsCmd.MarkFlagRequired("instance-id")
+
+	return listVnicAttachmentsCmd
+}
+
+// newVirtualNetworkClient creates the client for VCN, subnet, VNIC and public IP operations.
+func newVirtualNetworkClient(configProvider common.ConfigurationProvider) (core.VirtualNetworkClient, error) {
+	client, err := core.NewVirtualNetworkClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return client, fmt.Errorf("failed to create virtual network client: %w", err)
+	}
+	return client, nil
+}
+
+func displayVnicDetails(vnic *core.Vnic) {
+	fmt.Println("VNIC Details:")
+	fmt.Printf("  ID: %s\n", stringValue(vnic.Id))
+	fmt.Printf("  Display Name: %s\n", stringValue(vnic.DisplayName))
+	fmt.Printf("  State: %s\n", vnic.LifecycleState)
+	fmt.Printf("  Primary: %t\n", vnic.IsPrimary != nil && *vnic.IsPrimary)
+	fmt.Printf("  Private IP: %s\n", stringValue(vnic.PrivateIp))
+	fmt.Printf("  Public IP: %s\n", stringValue(vnic.PublicIp))
+	fmt.Printf("  Hostname Label: %s\n", stringValue(vnic.HostnameLabel))
+	fmt.Printf("  MAC Address: %s\n", stringValue(vnic.MacAddress))
+	fmt.Printf("  Subnet ID: %s\n", stringValue(vnic.SubnetId))
+	fmt.Printf("  Skip Source/Dest Check: %t\n", vnic.SkipSourceDestCheck != nil && *vnic.SkipSourceDestCheck)
+	if len(vnic.NsgIds) == 0 {
+		fmt.Println("  NSGs: (none)")
+	} else {
+		fmt.Println("  NSGs:")
+		for _, nsgID := range vnic.NsgIds {
+			fmt.Printf("    - %s\n", nsgID)
+		}
+	}
+}
+
+// vnicJSON is the IP information of one VNIC attached to an instance.
+type vnicJSON struct {
+	VnicID    string `json:"vnicId"`
+	PrivateIP string `json:"privateIp"`
+	PublicIP  string `json:"publicIp"`
+	Hostname  string `json:"hostname,omitempty"`
+	SubnetID  string `json:"subnetId,omitempty"`
+	Primary   bool   `json:"primary"`
+}
+
+// vnicAttachmentJSON is the serializable projection of a VNIC attachment.
+type vnicAttachmentJSON struct {
+	ID             string `json:"id"`
+	VnicID         string `json:"vnicId,omitempty"`
+	SubnetID       string `json:"subnetId,omitempty"`
+	NicIndex       int    `json:"nicIndex"`
+	LifecycleState string `json:"lifecycleState"`
+}
+
+func newVnicAttachmentJSON(attachment core.VnicAttachment) vnicAttachmentJSON {
+	result := vnicAttachmentJSON{
+		ID:             stringValue(attachment.Id),
+		VnicID:         stringValue(attachment.VnicId),
+		SubnetID:       stringValue(attachment.SubnetId),
+		LifecycleState: string(attachment.LifecycleState),
+	}
+	if attachment.NicIndex != nil {
+		result.NicIndex = *attachment.NicIndex
+	}
+	return result
+}
+
+// subnetJSON is the serializable projection of a subnet.
+type subnetJSON struct {
+	ID                 string `json:"id"`
+	DisplayName        string `json:"displayName"`
+	CidrBlock          string `json:"cidrBlock"`
+	VcnID              string `json:"vcnId"`
+	AvailabilityDomain string `json:"availabilityDomain,omitempty"`
+	Public             bool   `json:"public"`
+}
+
+func newSubnetJSON(subnet core.Subnet) subnetJSON {
+	return subnetJSON{
+		ID:                 stringValue(subnet.Id),
+		DisplayName:        stringValue(subnet.DisplayName),
+		CidrBlock:          stringValue(subnet.CidrBlock),
+		VcnID:              stringValue(subnet.VcnId),
+		AvailabilityDomain: stringValue(subnet.AvailabilityDomain),
+		Public:             subnet.ProhibitPublicIpOnVnic == nil || !*subnet.ProhibitPublicIpOnVnic,
+	}
+}
+
+// vcnJSON is the serializable projection of a VCN.
+type vcnJSON struct {
+	ID             string   `json:"id"`
+	DisplayName    string   `json:"displayName"`
+	CidrBlocks     []string `json:"cidrBlocks"`
+	DNSLabel       string   `json:"dnsLabel,omitempty"`
+	LifecycleState string   `json:"lifecycleState"`
+}
+
+func newVcnJSON(vcn core.Vcn) vcnJSON {
+	return vcnJSON{
+		ID:             stringValue(vcn.Id),
+		DisplayName:    stringValue(vcn.DisplayName),
+		CidrBlocks:     vcn.CidrBlocks,
+		DNSLabel:       stringValue(vcn.DnsLabel),
+		LifecycleState: string(vcn.LifecycleState),
+	}
+}
+
+// resolveInstanceVnics returns the IP details of every VNIC attached to an instance.
+// Instances without attached VNICs yield an empty, non-nil slice.
+func resolveInstanceVnics(computeClient core.ComputeClient, networkClient core.VirtualNetworkClient, compartmentID, instanceID string) ([]vnicJSON, error) {
+	vnics := []vnicJSON{}
+
+	request := core.ListVnicAttachmentsRequest{
+		CompartmentId: &compartmentID,
+		InstanceId:    &instanceID,
+	}
+	for {
+		response, err := apiCall(computeClient.ListVnicAttachments, request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list VNIC attachments for instance %s: %w", instanceID, err)
+		}
+
+		for _, attachment := range response.Items {
+			if attachment.LifecycleState != core.VnicAttachmentLifecycleStateAttached || attachment.VnicId == nil {
+				continue
+			}
+			vnicResponse, err := apiCall(networkClient.GetVnic, core.GetVnicRequest{VnicId: attachment.VnicId})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get VNIC %s: %w", *attachment.VnicId, err)
+			}
+			vnic := vnicResponse.Vnic
+			vnics = append(vnics, vnicJSON{
+				VnicID:    stringValue(vnic.Id),
+				PrivateIP: stringValue(vnic.PrivateIp),
+				PublicIP:  stringValue(vnic.PublicIp),
+				Hostname:  stringValue(vnic.HostnameLabel),
+				SubnetID:  stringValue(vnic.SubnetId),
+				Primary:   vnic.IsPrimary != nil && *vnic.IsPrimary,
+			})
+		}
+
+		if response.OpcNextPage == nil {
+			break
+		}
+		request.Page = response.OpcNextPage
+	}
+
+	return vnics, nil
+}
+
+// resolveInstancesVnics resolves the VNICs of many instances using at most concurrency
+// parallel lookups. The result is indexed like instances.
+func resolveInstancesVnics(computeClient core.ComputeClient, networkClient core.VirtualNetworkClient, instances []core.Instance, concurrency int) ([][]vnicJSON, error) {
+	results := make([][]vnicJSON, len(instances))
+	err := runConcurrently(len(instances), concurrency, func(i int) error {
+		var err error
+		results[i], err = resolveInstanceVnics(computeClient, networkClient, *instances[i].CompartmentId, *instances[i].Id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// resolveReservedPublicIPNameToID finds the OCID of a reserved public IP by display name.
+func resolveReservedPublicIPNameToID(ipName, compartmentID string, client core.VirtualNetworkClient) (string, error) {
+	request := core.ListPublicIpsRequest{
+		Scope:         core.ListPublicIpsScopeRegion,
+		Lifetime:      core.ListPublicIpsLifetimeReserved,
+		CompartmentId: &compartmentID,
+	}
+	for {
+		response, err := apiCall(client.ListPublicIps, request)
+		if err != nil {
+			return "", fmt.Errorf("failed to list reserved public IPs: %w", err)
+		}
+		for _, publicIP := range response.Items {
+			if publicIP.DisplayName != nil && *publicIP.DisplayName == ipName {
+				return *publicIP.Id, nil
+			}
+		}
+		if response.OpcNextPage == nil {
+			break
+		}
+		request.Page = response.OpcNextPage
+	}
+	return "", fmt.Errorf("no reserved public IP found with name '%s' in compartment '%s'", ipName, compartmentID)
+}
+
+// waitForPrimaryVnic waits until an instance's primary VNIC is attached and returns its OCID.
+func waitForPrimaryVnic(computeClient core.ComputeClient, networkClient core.VirtualNetworkClient, compartmentID, instanceID string, timeout time.Duration) (string, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		vnics, err := resolveInstanceVnics(computeClient, networkClient, compartmentID, instanceID)
+		if err != nil {
+			return "", err
+		}
+		for _, vnic := range vnics {
+			if vnic.Primary {
+				return vnic.VnicID, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("primary VNIC of instance %s not attached after %s", instanceID, timeout)
+		}
+		pause(5 * time.Second)
+	}
+}
+
+// assignReservedPublicIP associates a reserved public IP with the primary private IP of a VNIC
+// and returns the assigned address.
+func assignReservedPublicIP(client core.VirtualNetworkClient, publicIPID, vnicID string) (string, error) {
+	privateIPs, err := apiCall(client.ListPrivateIps, core.ListPrivateIpsRequest{VnicId: &vnicID})
+	if err != nil {
+		return "", fmt.Errorf("failed to list private IPs of VNIC %s: %w", vnicID, err)
+	}
+	var privateIPID *string
+	for _, privateIP := range privateIPs.Items {
+		if privateIP.IsPrimary != nil && *privateIP.IsPrimary {
+			privateIPID = privateIP.Id
+			break
+		}
+	}
+	if privateIPID == nil {
+		return "", fmt.Errorf("VNIC %s has no primary private IP", vnicID)
+	}
+
+	response, err := apiCall(client.UpdatePublicIp, core.UpdatePublicIpRequest{
+		PublicIpId: &publicIPID,
+		UpdatePublicIpDetails: core.UpdatePublicIpDetails{
+			PrivateIpId: privateIPID,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to assign reserved public IP %s: %w", publicIPID, err)
+	}
+	return stringValue(response.PublicIp.IpAddress), nil
+}
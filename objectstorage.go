@@ -0,0 +1,448 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/objectstorage"
+	"github.com/spf13/cobra"
+)
+
+func newObjectStorageCmd() *cobra.Command {
+	var objectStorageCmd = &cobra.Command{
+		Use:     "objectstorage",
+		Aliases: []string{"object-storage"},
+		Short:   "Manage object storage buckets and objects",
+	}
+
+	var bucketsCmd = &cobra.Command{
+		Use:     "buckets",
+		Aliases: []string{"bucket"},
+		Short:   "Manage object storage buckets",
+	}
+
+	var createBucketCmd = &cobra.Command{
+		Use:   "create",
+		Short: "Create an object storage bucket",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nameFlag, _ := cmd.Flags().GetString("name")
+			compartmentInput, _ := cmd.Flags().GetString("compartment-id")
+			tierFlag, _ := cmd.Flags().GetString("tier")
+			publicAccessFlag, _ := cmd.Flags().GetString("public-access")
+
+			tier, ok := objectstorage.GetMappingCreateBucketDetailsStorageTierEnum(tierFlag)
+			if !ok {
+				return fmt.Errorf("invalid --tier '%s' (valid: %s)", tierFlag, strings.Join(objectstorage.GetCreateBucketDetailsStorageTierEnumStringValues(), ", "))
+			}
+			publicAccess, ok := objectstorage.GetMappingCreateBucketDetailsPublicAccessTypeEnum(publicAccessFlag)
+			if !ok {
+				return fmt.Errorf("invalid --public-access '%s' (valid: %s)", publicAccessFlag, strings.Join(objectstorage.GetCreateBucketDetailsPublicAccessTypeEnumStringValues(), ", "))
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			var compartmentID string
+			if compartmentInput != "" {
+				compartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
+				if err != nil {
+					return fmt.Errorf("resolving compartment ID '%s': %w", compartmentInput, err)
+				}
+			} else {
+				compartmentID, err = configProvider.TenancyOCID()
+				if err != nil {
+					return fmt.Errorf("getting tenancy OCID: %w", err)
+				}
+			}
+
+			client, err := objectstorage.NewObjectStorageClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating object storage client: %w", err)
+			}
+			namespace, err := getNamespace(client)
+			if err != nil {
+				return err
+			}
+
+			response, err := apiCall(client.CreateBucket, objectstorage.CreateBucketRequest{
+				NamespaceName: &namespace,
+				CreateBucketDetails: objectstorage.CreateBucketDetails{
+					Name:             &nameFlag,
+					CompartmentId:    &compartmentID,
+					StorageTier:      tier,
+					PublicAccessType: publicAccess,
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("creating bucket: %w", err)
+			}
+			fmt.Printf("Bucket created successfully.\nName: %s\nNamespace: %s\nStorage Tier: %s\nPublic Access: %s\n", *response.Bucket.Name, namespace, response.Bucket.StorageTier, response.Bucket.PublicAccessType)
+			return nil
+		},
+	}
+
+	createBucketCmd.Flags().String("name", "", "Name of the bucket (Required)")
+	createBucketCmd.Flags().String("compartment-id", "", "(Optional) OCID, name or path of the compartment to create the bucket in (defaults to tenancy root)")
+	createBucketCmd.Flags().String("tier", "Standard", "(Optional) Storage tier: Standard or Archive")
+	createBucketCmd.Flags().String("public-access", "NoPublicAccess", "(Optional) Public access type: NoPublicAccess, ObjectRead or ObjectReadWithoutList")
+	_ = createBucketCmd.MarkFlagRequired("name")
+
+	var deleteBucketCmd = &cobra.Command{
+		Use:   "delete",
+		Short: "Delete an object storage bucket",
+		Long: `Deletes an object storage bucket. Non-empty buckets are refused unless --force is given,
+in which case every object in the bucket is deleted first.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nameFlag, _ := cmd.Flags().GetString("name")
+			forceFlag, _ := cmd.Flags().GetBool("force")
+			confirmFlag, _ := cmd.Flags().GetBool("confirm")
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := objectstorage.NewObjectStorageClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating object storage client: %w", err)
+			}
+			namespace, err := getNamespace(client)
+			if err != nil {
+				return err
+			}
+
+			objects, err := listObjectNames(client, namespace, nameFlag)
+			if err != nil {
+				return err
+			}
+			if len(objects) > 0 && !forceFlag {
+				return fmt.Errorf("bucket '%s' contains %d object(s); use --force to delete them first", nameFlag, len(objects))
+			}
+
+			if !confirmFlag {
+				message := fmt.Sprintf("Delete bucket '%s'?", nameFlag)
+				if len(objects) > 0 {
+					message = fmt.Sprintf("Delete bucket '%s' and its %d object(s)?", nameFlag, len(objects))
+				}
+				if !confirmPrompt(message) {
+					return errors.New("aborted")
+				}
+			}
+
+			for _, object := range objects {
+				objectName := object
+				_, err := apiCall(client.DeleteObject, objectstorage.DeleteObjectRequest{
+					NamespaceName: &namespace,
+					BucketName:    &nameFlag,
+					ObjectName:    &objectName,
+				})
+				if err != nil {
+					return fmt.Errorf("deleting object '%s': %w", objectName, err)
+				}
+				fmt.Printf("Deleted object %s\n", objectName)
+			}
+
+			_, err = apiCall(client.DeleteBucket, objectstorage.DeleteBucketRequest{
+				NamespaceName: &namespace,
+				BucketName:    &nameFlag,
+			})
+			if err != nil {
+				return fmt.Errorf("deleting bucket: %w", err)
+			}
+			fmt.Printf("Bucket '%s' deleted.\n", nameFlag)
+			return nil
+		},
+	}
+
+	deleteBucketCmd.Flags().String("name", "", "Name of the bucket (Required)")
+	deleteBucketCmd.Flags().Bool("force", false, "(Optional) Delete all objects in the bucket before deleting it")
+	deleteBucketCmd.Flags().Bool("confirm", false, "(Optional) Skip the interactive confirmation")
+	_ = deleteBucketCmd.MarkFlagRequired("name")
+
+	var listBucketsCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the object storage buckets of a compartment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			compartmentInput, _ := cmd.Flags().GetString("compartment-id")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			var compartmentID string
+			if compartmentInput != "" {
+				compartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
+				if err != nil {
+					return fmt.Errorf("resolving compartment ID '%s': %w", compartmentInput, err)
+				}
+			} else {
+				compartmentID, err = configProvider.TenancyOCID()
+				if err != nil {
+					return fmt.Errorf("getting tenancy OCID: %w", err)
+				}
+			}
+
+			client, err := objectstorage.NewObjectStorageClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating object storage client: %w", err)
+			}
+			namespace, err := getNamespace(client)
+			if err != nil {
+				return err
+			}
+
+			request := objectstorage.ListBucketsRequest{
+				NamespaceName: &namespace,
+				CompartmentId: &compartmentID,
+				Limit:         common.Int(pageSize),
+			}
+			buckets, truncated, err := collectPages(maxItems(cmd), func(page *string) ([]objectstorage.BucketSummary, *string, error) {
+				request.Page = page
+				response, err := apiCall(client.ListBuckets, request)
+				return response.Items, response.OpcNextPage, err
+			})
+			if err != nil {
+				return fmt.Errorf("listing buckets: %w", err)
+			}
+
+			items := make([]bucketJSON, len(buckets))
+			for i, bucket := range buckets {
+				items[i] = newBucketJSON(bucket)
+			}
+			if isStructuredOutput(output) {
+				region, _ := configProvider.Region()
+				if err := printList(output, items, listMeta{Count: len(items), Truncated: truncated, Region: region}); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			if len(items) == 0 {
+				fmt.Println("No buckets found.")
+				return nil
+			}
+			table := newTextTable(cmd,
+				tableColumn{header: "NAME"},
+				tableColumn{header: "NAMESPACE"},
+				tableColumn{header: "CREATED"},
+			)
+			for _, bucket := range items {
+				table.addRow(bucket.Name, bucket.Namespace, bucket.TimeCreated)
+			}
+			table.print()
+			return nil
+		},
+	}
+
+	listBucketsCmd.Flags().String("compartment-id", "", "(Optional) OCID, name or path of the compartment to list buckets from (defaults to tenancy root)")
+	addMaxItemsFlag(listBucketsCmd)
+
+	bucketsCmd.AddCommand(createBucketCmd, deleteBucketCmd, listBucketsCmd)
+	var putCmd = &cobra.Command{
+		Use:   "put",
+		Short: "Upload a local file as an object",
+		Long: `Uploads a local file as a single object. The file is streamed rather than loaded into
+memory; the Content-Type is derived from its extension unless --content-type is given.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bucketFlag, _ := cmd.Flags().GetString("bucket")
+			nameFlag, _ := cmd.Flags().GetString("name")
+			fileFlag, _ := cmd.Flags().GetString("file")
+			contentTypeFlag, _ := cmd.Flags().GetString("content-type")
+
+			file, err := os.Open(fileFlag)
+			if err != nil {
+				return fmt.Errorf("opening '%s': %w", fileFlag, err)
+			}
+			defer file.Close()
+			info, err := file.Stat()
+			if err != nil {
+				return fmt.Errorf("reading '%s': %w", fileFlag, err)
+			}
+			if nameFlag == "" {
+				nameFlag = filepath.Base(fileFlag)
+			}
+			contentType := contentTypeFlag
+			if contentType == "" {
+				contentType = mime.TypeByExtension(filepath.Ext(fileFlag))
+			}
+			if contentType == "" {
+				contentType = "application/octet-stream"
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := objectstorage.NewObjectStorageClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating object storage client: %w", err)
+			}
+			namespace, err := getNamespace(client)
+			if err != nil {
+				return err
+			}
+
+			start := time.Now()
+			response, err := apiCall(client.PutObject, objectstorage.PutObjectRequest{
+				NamespaceName: &namespace,
+				BucketName:    &bucketFlag,
+				ObjectName:    &nameFlag,
+				ContentLength: common.Int64(info.Size()),
+				ContentType:   &contentType,
+				PutObjectBody: file,
+			})
+			if err != nil {
+				return fmt.Errorf("uploading object: %w", err)
+			}
+			fmt.Printf("Uploaded %s to %s/%s (%d bytes, %s, %s).\nETag: %s\n", fileFlag, bucketFlag, nameFlag, info.Size(), contentType, time.Since(start).Round(time.Millisecond), stringValue(response.ETag))
+			return nil
+		},
+	}
+
+	putCmd.Flags().String("bucket", "", "Name of the bucket (Required)")
+	putCmd.Flags().String("name", "", "(Optional) Object name (defaults to the file's base name)")
+	putCmd.Flags().String("file", "", "Path of the local file to upload (Required)")
+	putCmd.Flags().String("content-type", "", "(Optional) Content-Type of the object (detected from the file extension by default)")
+	_ = putCmd.MarkFlagRequired("bucket")
+	_ = putCmd.MarkFlagRequired("file")
+
+	var getCmd = &cobra.Command{
+		Use:   "get",
+		Short: "Download an object to a local file",
+		Long:  `Downloads a single object, streaming it to --file without loading it into memory.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bucketFlag, _ := cmd.Flags().GetString("bucket")
+			nameFlag, _ := cmd.Flags().GetString("name")
+			fileFlag, _ := cmd.Flags().GetString("file")
+
+			if fileFlag == "" {
+				fileFlag = filepath.Base(nameFlag)
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := objectstorage.NewObjectStorageClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating object storage client: %w", err)
+			}
+			namespace, err := getNamespace(client)
+			if err != nil {
+				return err
+			}
+
+			start := time.Now()
+			response, err := apiCall(client.GetObject, objectstorage.GetObjectRequest{
+				NamespaceName: &namespace,
+				BucketName:    &bucketFlag,
+				ObjectName:    &nameFlag,
+			})
+			if err != nil {
+				return fmt.Errorf("downloading object: %w", err)
+			}
+			defer response.Content.Close()
+
+			written, err := writeStreamToFile(fileFlag, response.Content)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Downloaded %s/%s to %s (%d bytes, %s).\n", bucketFlag, nameFlag, fileFlag, written, time.Since(start).Round(time.Millisecond))
+			return nil
+		},
+	}
+
+	getCmd.Flags().String("bucket", "", "Name of the bucket (Required)")
+	getCmd.Flags().String("name", "", "Name of the object (Required)")
+	getCmd.Flags().String("file", "", "(Optional) Local path to write to (defaults to the object's base name)")
+	_ = getCmd.MarkFlagRequired("bucket")
+	_ = getCmd.MarkFlagRequired("name")
+
+	objectStorageCmd.AddCommand(bucketsCmd, putCmd, getCmd)
+
+	return objectStorageCmd
+}
+
+// bucketJSON is the projection of a bucket summary used for structured output.
+type bucketJSON struct {
+	Name          string `json:"name"`
+	Namespace     string `json:"namespace"`
+	CompartmentID string `json:"compartmentId"`
+	TimeCreated   string `json:"timeCreated,omitempty"`
+}
+
+func newBucketJSON(bucket objectstorage.BucketSummary) bucketJSON {
+	result := bucketJSON{
+		Name:          stringValue(bucket.Name),
+		Namespace:     stringValue(bucket.Namespace),
+		CompartmentID: stringValue(bucket.CompartmentId),
+	}
+	if bucket.TimeCreated != nil {
+		result.TimeCreated = bucket.TimeCreated.Format(time.RFC3339)
+	}
+	return result
+}
+
+// writeStreamToFile copies r to path and returns the number of bytes written. A partially
+// written file is removed on failure.
+func writeStreamToFile(path string, r io.Reader) (int64, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("creating '%s': %w", path, err)
+	}
+	written, err := io.Copy(file, r)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return written, fmt.Errorf("writing '%s': %w", path, err)
+	}
+	return written, nil
+}
+
+// getNamespace returns the object storage namespace of the tenancy.
+func getNamespace(client objectstorage.ObjectStorageClient) (string, error) {
+	response, err := apiCall(client.GetNamespace, objectstorage.GetNamespaceRequest{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get object storage namespace: %w", err)
+	}
+	return *response.Value, nil
+}
+
+// listObjectNames returns the names of all objects in a bucket, following pagination.
+func listObjectNames(client objectstorage.ObjectStorageClient, namespace, bucket string) ([]string, error) {
+	var names []string
+	request := objectstorage.ListObjectsRequest{
+		NamespaceName: &namespace,
+		BucketName:    &bucket,
+	}
+	for {
+		response, err := apiCall(client.ListObjects, request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects in bucket '%s': %w", bucket, err)
+		}
+		for _, object := range response.Objects {
+			names = append(names, *object.Name)
+		}
+		if response.NextStartWith == nil {
+			return names, nil
+		}
+		request.Start = response.NextStartWith
+	}
+}
@@ -2,27 +2,57 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/oracle/oci-go-sdk/v65/common"
 	"github.com/oracle/oci-go-sdk/v65/core"
 	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/oracle/oci-go-sdk/v65/workrequests"
 	"github.com/spf13/cobra"
 )
 
+// exitCodeError is a command error that carries a specific process exit code.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string { return e.err.Error() }
+
+func (e *exitCodeError) Unwrap() error { return e.err }
+
 func main() {
 	var rootCmd = &cobra.Command{
-		Use: "oci-cli",
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			fmt.Printf("Debug: Executing command: %s\n", cmd.CommandPath())
+		Use:           "oci-cli",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyLogLevel(cmd); err != nil {
+				return err
+			}
+			slog.Debug("executing command", "command", cmd.CommandPath())
+			applyProfileDefaults(cmd)
+			applyCacheFlags(cmd)
+			return applyTimeoutFlag(cmd)
 		},
 	}
 
 	rootCmd.PersistentFlags().String("profile", "", "Specify the OCI config profile to use")
+	rootCmd.PersistentFlags().String("region", "", "Region to use instead of the profile's (e.g. us-ashburn-1 or iad)")
+	rootCmd.PersistentFlags().String("auth", "config", "Authentication method: 'config' (OCI config file), 'instance-principal' or 'resource-principal'")
+	rootCmd.PersistentFlags().String("config-file", "", "Path of the OCI config file (defaults to $OCI_CLI_CONFIG_FILE or ~/.oci/config)")
+	rootCmd.PersistentFlags().Duration("cache-ttl", defaultCompartmentCacheTTL, "How long cached compartment listings (~/.oci-cli-cache) are reused for name/path resolution")
+	rootCmd.PersistentFlags().Bool("no-cache", false, "Bypass the compartment and image name caches and always query the API")
+	rootCmd.PersistentFlags().String("log-level", "warn", "Log level for diagnostics on stderr: 'error', 'warn', 'info' or 'debug'")
+	rootCmd.PersistentFlags().Duration("timeout", defaultAPITimeout, "Deadline for each OCI API call (e.g. 30s, 2m; 0 disables it). --wait loops keep their own longer timeout")
+	rootCmd.PersistentFlags().String("output", "text", "Output format: 'text', 'json', 'json-meta' (JSON lists wrapped with count/truncated/region metadata), 'yaml' or 'csv'")
 
 	var instancesCmd = &cobra.Command{
 		Use:   "instances",
@@ -32,89 +62,357 @@ func main() {
 	var listCmd = &cobra.Command{
 		Use:   "list",
 		Short: "List instances in a compartment or tenancy",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			compartmentInput, _ := cmd.Flags().GetString("compartment-id")
 			tenancyFlag, _ := cmd.Flags().GetString("tenancy")
-			profileFlag, _ := cmd.Flags().GetString("profile")
-			var configProvider common.ConfigurationProvider
+			includeIPsFlag, _ := cmd.Flags().GetBool("include-ips")
+			withIPsFlag, _ := cmd.Flags().GetBool("with-ips")
+			concurrencyFlag, _ := cmd.Flags().GetInt("concurrency")
+			groupByFlag, _ := cmd.Flags().GetString("group-by")
+			allPagesFlag, _ := cmd.Flags().GetBool("all-pages")
+			stateFlag, _ := cmd.Flags().GetString("state")
+			sortByFlag, _ := cmd.Flags().GetString("sort-by")
+			sortOrderFlag, _ := cmd.Flags().GetString("sort-order")
+			recursiveFlag, _ := cmd.Flags().GetBool("recursive")
+			freeformTagFlags, _ := cmd.Flags().GetStringArray("freeform-tag")
+			fieldsFlag, _ := cmd.Flags().GetString("fields")
 			var err error
 
-			if profileFlag != "" {
-				configProvider = common.CustomProfileConfigProvider("~/.oci/config", profileFlag)
-			} else {
-				configProvider = common.DefaultConfigProvider()
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+			tagFilter, err := parseFreeformTags(freeformTagFlags)
+			if err != nil {
+				return err
+			}
+			fields, err := parseInstanceFields(fieldsFlag)
+			if err != nil {
+				return err
+			}
+			if withIPsFlag || fieldsNeedVnics(fields) {
+				includeIPsFlag = true
+			}
+			if groupByFlag != "" {
+				if _, err := instanceGroupKey(core.Instance{}, groupByFlag, nil); err != nil {
+					return err
+				}
+				if output == "csv" {
+					return errors.New("--group-by is not supported with --output csv")
+				}
+			}
+			var state core.InstanceLifecycleStateEnum
+			if stateFlag != "" {
+				var ok bool
+				state, ok = core.GetMappingInstanceLifecycleStateEnum(stateFlag)
+				if !ok {
+					return fmt.Errorf("invalid --state '%s' (must be one of %s)", stateFlag, strings.Join(core.GetInstanceLifecycleStateEnumStringValues(), ", "))
+				}
+			}
+			sortBy, ok := core.GetMappingListInstancesSortByEnum(sortByFlag)
+			if !ok {
+				return fmt.Errorf("invalid --sort-by '%s' (must be one of %s)", sortByFlag, strings.Join(core.GetListInstancesSortByEnumStringValues(), ", "))
+			}
+			sortOrder, ok := core.GetMappingListInstancesSortOrderEnum(sortOrderFlag)
+			if !ok {
+				return fmt.Errorf("invalid --sort-order '%s' (must be one of %s)", sortOrderFlag, strings.Join(core.GetListInstancesSortOrderEnumStringValues(), ", "))
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
 			}
 
 			var compartmentID string
 			if tenancyFlag != "" {
 				tenancyOCID, err := configProvider.TenancyOCID()
 				if err != nil {
-					log.Fatalf("Error getting tenancy OCID: %v", err)
+					return fmt.Errorf("getting tenancy OCID: %w", err)
 				}
 				compartmentID = tenancyOCID
 			} else if compartmentInput != "" {
 				compartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
 				if err != nil {
-					log.Fatalf("Error resolving compartment: %v", err)
+					return fmt.Errorf("resolving compartment: %w", err)
 				}
 			} else {
 				tenancyOCID, err := configProvider.TenancyOCID()
 				if err != nil {
-					log.Fatalf("Error getting tenancy OCID for default: %v", err)
+					return fmt.Errorf("getting tenancy OCID for default: %w", err)
 				}
 				compartmentID = tenancyOCID
 			}
 
 			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
 			if err != nil {
-				log.Fatalf("Error creating compute client: %v", err)
+				return fmt.Errorf("creating compute client: %w", err)
 			}
 
+			// With --recursive every compartment below the selected one is listed as well
+			compartmentIDs := []string{compartmentID}
+			compartmentNames := map[string]string{}
+			// Paths such as "root/team/prod" label the groups of --group-by compartment, since
+			// names alone need not be unique
+			compartmentPathsByID := map[string]string{}
+			if recursiveFlag {
+				identityClient, err := identity.NewIdentityClientWithConfigurationProvider(configProvider)
+				if err != nil {
+					return fmt.Errorf("creating identity client: %w", err)
+				}
+				tenancyOCID, err := configProvider.TenancyOCID()
+				if err != nil {
+					return fmt.Errorf("getting tenancy OCID: %w", err)
+				}
+				compartments, err := listSubtreeCompartments(identityClient, tenancyOCID)
+				if err != nil {
+					return fmt.Errorf("listing compartments: %w", err)
+				}
+				compartmentNames[tenancyOCID] = "root"
+				for _, compartment := range compartments {
+					compartmentNames[*compartment.Id] = stringValue(compartment.Name)
+				}
+				compartmentPathsByID = compartmentPaths(compartments, tenancyOCID)
+				compartmentIDs = append(compartmentIDs, descendantCompartmentIDs(compartments, compartmentID)...)
+			}
+
+			maxPages := 0
+			if !allPagesFlag {
+				maxPages = 1
+			}
+			limit := maxItems(cmd)
+			// The tag filter is applied client-side, so the cap is applied after filtering
+			scanLimit := limit
+			if len(tagFilter) > 0 {
+				scanLimit = 0
+			}
 			request := core.ListInstancesRequest{
-				CompartmentId: &compartmentID,
+				Limit:          common.Int(pageSize),
+				LifecycleState: state,
+				SortBy:         sortBy,
+				SortOrder:      sortOrder,
 			}
-			response, err := computeClient.ListInstances(context.Background(), request)
+			instances, truncated, err := listInstancesInCompartments(computeClient, request, compartmentIDs, compartmentNames, scanLimit, maxPages, concurrencyFlag)
 			if err != nil {
-				log.Fatal(err)
+				return err
+			}
+			if len(tagFilter) > 0 {
+				instances = filterInstancesByFreeformTags(instances, tagFilter)
+				if limit > 0 && len(instances) > limit {
+					instances = instances[:limit]
+					truncated = true
+				}
+			}
+
+			var instanceVnics [][]vnicJSON
+			if includeIPsFlag {
+				networkClient, err := newVirtualNetworkClient(configProvider)
+				if err != nil {
+					return err
+				}
+				instanceVnics, err = resolveInstancesVnics(computeClient, networkClient, instances, concurrencyFlag)
+				if err != nil {
+					return fmt.Errorf("resolving instance IPs: %w", err)
+				}
+			}
+
+			if isStructuredOutput(output) {
+				items := make([]interface{}, len(instances))
+				for i, instance := range instances {
+					details := newInstanceJSON(&instance)
+					details.CompartmentName = compartmentNames[details.CompartmentID]
+					if len(fields) > 0 {
+						record := instanceRecord{instance: instance, compartmentName: details.CompartmentName}
+						if includeIPsFlag {
+							record.vnics = instanceVnics[i]
+						}
+						items[i] = projectInstance(fields, record)
+					} else if includeIPsFlag {
+						items[i] = newInstanceWithVnicsJSON(details, instanceVnics[i])
+					} else {
+						items[i] = details
+					}
+				}
+				var result interface{} = items
+				if groupByFlag != "" {
+					grouped := map[string][]interface{}{}
+					keys, groups := groupInstances(instances, groupByFlag, compartmentPathsByID)
+					for _, key := range keys {
+						for _, i := range groups[key] {
+							grouped[key] = append(grouped[key], items[i])
+						}
+					}
+					result = grouped
+				}
+				region, _ := configProvider.Region()
+				meta := listMeta{Count: len(items), Truncated: truncated, Region: region}
+				if err := printList(output, result, meta); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+
+			table := instanceTable{
+				instances:        instances,
+				compartmentNames: compartmentNames,
+				showCompartment:  recursiveFlag,
+				showIPs:          includeIPsFlag,
+				vnics:            instanceVnics,
+				fields:           fields,
+			}
+
+			if groupByFlag != "" {
+				keys, groups := groupInstances(instances, groupByFlag, compartmentPathsByID)
+				for _, key := range keys {
+					fmt.Printf("=== %s: %s (%d) ===\n", groupByFlag, key, len(groups[key]))
+					table.print(cmd, groups[key])
+				}
+				return nil
 			}
 
-			for _, instance := range response.Items {
-				fmt.Printf("Instance ID: %s, Display Name: %s, State: %s\n", *instance.Id, *instance.DisplayName, instance.LifecycleState)
+			rows := make([]int, len(instances))
+			for i := range instances {
+				rows[i] = i
 			}
+			table.print(cmd, rows)
+			return nil
 		},
 	}
 
-	listCmd.Flags().String("compartment-id", "", "The OCID or friendly name of the compartment to list instances from")
+	listCmd.Flags().String("compartment-id", "", "The OCID, friendly name or path (e.g. 'team/prod') of the compartment to list instances from")
 	listCmd.Flags().String("tenancy", "", "Use the tenancy to list instances (ignores --compartment-id)")
+	listCmd.Flags().Bool("recursive", false, "Also list instances in all sub-compartments, prefixing each with its compartment name")
+	listCmd.Flags().Bool("include-ips", false, "Resolve and show the private/public IPs of each instance's VNICs (extra API calls)")
+	listCmd.Flags().Bool("with-ips", false, "Same as --include-ips; structured output also gets top-level privateIp/publicIp fields from the primary VNIC")
+	listCmd.Flags().Int("concurrency", 8, "Maximum number of parallel lookups used by --recursive and --include-ips")
+	addMaxItemsFlag(listCmd)
+	listCmd.Flags().Bool("all-pages", true, "Follow pagination to return every instance; use --all-pages=false to fetch only the first page")
+	listCmd.Flags().String("state", "", "(Optional) Only list instances in this lifecycle state (e.g. RUNNING, STOPPED, TERMINATED; case-insensitive)")
+	listCmd.Flags().String("sort-by", "TIMECREATED", "Sort field: TIMECREATED or DISPLAYNAME")
+	listCmd.Flags().String("sort-order", "DESC", "Sort order: ASC or DESC")
+	addFieldsFlag(listCmd)
+	listCmd.Flags().StringArray("freeform-tag", nil, "(Optional, repeatable) Only list instances with this freeform tag, as key=value; several tags must all match. Filtering is client-side after pagination, so it does not reduce API calls")
+	addNoTruncateFlag(listCmd)
+	listCmd.Flags().String("group-by", "", "(Optional) Group instances by compartment, shape, state, ad or fault-domain; with --recursive compartments are shown by path, otherwise by OCID")
 
 	var createCmd = &cobra.Command{
 		Use:   "create",
 		Short: "Create a new compute instance",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			// 1. Get Flags
-			profileFlag, _ := cmd.Flags().GetString("profile")
 			nameFlag, _ := cmd.Flags().GetString("name")
 			compartmentInput, _ := cmd.Flags().GetString("compartment-id")
 			shapeNameFlag, _ := cmd.Flags().GetString("shape-name")
 			imageNameFlag, _ := cmd.Flags().GetString("image-name")
+			strictImageMatchFlag, _ := cmd.Flags().GetBool("strict-image-match")
+			osFlag, _ := cmd.Flags().GetString("os")
+			bootVolumeIDFlag, _ := cmd.Flags().GetString("boot-volume-id")
+			bootVolumeSizeFlag, _ := cmd.Flags().GetInt64("boot-volume-size-in-gbs")
+			osVersionFlag, _ := cmd.Flags().GetString("os-version")
 			subnetIDFlag, _ := cmd.Flags().GetString("subnet-id")
+			subnetNameFlag, _ := cmd.Flags().GetString("subnet-name")
+			vcnIDFlag, _ := cmd.Flags().GetString("vcn-id")
 			adFlag, _ := cmd.Flags().GetString("availability-domain")
+			faultDomainFlag, _ := cmd.Flags().GetString("fault-domain")
 			publicKeysFlag, _ := cmd.Flags().GetString("public-keys")
+			publicKeyFileFlags, _ := cmd.Flags().GetStringArray("public-key-file")
+			defaultSSHKeyFlag, _ := cmd.Flags().GetBool("default-ssh-key")
+			userDataFileFlag, _ := cmd.Flags().GetString("user-data-file")
 			ocpusFlag, _ := cmd.Flags().GetFloat32("ocpus")
 			memoryInGBsFlag, _ := cmd.Flags().GetFloat32("memory-in-gbs")
+			baselineUtilizationFlag, _ := cmd.Flags().GetString("baseline-utilization")
+			autoLaunchOptionsFlag, _ := cmd.Flags().GetBool("auto-launch-options")
+			firmwareFlag, _ := cmd.Flags().GetString("firmware")
+			networkTypeFlag, _ := cmd.Flags().GetString("network-type")
+			bootVolumeTypeFlag, _ := cmd.Flags().GetString("boot-volume-type")
+			remoteDataVolumeTypeFlag, _ := cmd.Flags().GetString("remote-data-volume-type")
+			waitFlag, _ := cmd.Flags().GetBool("wait")
+			waitTimeoutFlag, _ := cmd.Flags().GetInt("wait-timeout")
+			teardownOnFailureFlag, _ := cmd.Flags().GetBool("teardown-on-failure")
+			validateOnlyFlag, _ := cmd.Flags().GetBool("validate-only")
+			dryRunFlag, _ := cmd.Flags().GetBool("dry-run")
+			onReadyExecFlag, _ := cmd.Flags().GetString("on-ready-exec")
+			waitForSSHFlag, _ := cmd.Flags().GetBool("wait-for-ssh")
+			capacityReservationIDFlag, _ := cmd.Flags().GetString("capacity-reservation-id")
+			preferredADFlag, _ := cmd.Flags().GetString("preferred-ad")
+			preferredADAttemptsFlag, _ := cmd.Flags().GetInt("preferred-ad-attempts")
+			tryAllADsFlag, _ := cmd.Flags().GetBool("try-all-ads")
+			placementRetryIntervalFlag, _ := cmd.Flags().GetInt("placement-retry-interval")
+			reservedPublicIPIDFlag, _ := cmd.Flags().GetString("reserved-public-ip-id")
+			reservedPublicIPNameFlag, _ := cmd.Flags().GetString("reserved-public-ip-name")
+			assignPublicIPFlag, _ := cmd.Flags().GetBool("assign-public-ip")
+			freeformTagFlags, _ := cmd.Flags().GetStringArray("freeform-tag")
+			definedTagFlags, _ := cmd.Flags().GetStringArray("defined-tag")
+
+			freeformTags, err := parseFreeformTags(freeformTagFlags)
+			if err != nil {
+				return err
+			}
+			definedTags, err := parseDefinedTags(definedTagFlags)
+			if err != nil {
+				return err
+			}
 
-			// 2. Setup Config Provider
-			var configProvider common.ConfigurationProvider
-			if profileFlag != "" {
-				configProvider = common.CustomProfileConfigProvider("~/.oci/config", profileFlag)
+			if adFlag != "" && preferredADFlag != "" {
+				return errors.New("specify either --availability-domain or --preferred-ad, not both")
+			}
+			if preferredADFlag != "" {
+				adFlag = preferredADFlag
 			} else {
-				configProvider = common.DefaultConfigProvider()
+				preferredADAttemptsFlag = 1
+			}
+			if subnetIDFlag != "" && subnetNameFlag != "" {
+				return errors.New("specify either --subnet-id or --subnet-name, not both")
+			}
+			if subnetIDFlag == "" && subnetNameFlag == "" {
+				return errors.New("specify --subnet-id or --subnet-name")
+			}
+			if reservedPublicIPIDFlag != "" && reservedPublicIPNameFlag != "" {
+				return errors.New("specify either --reserved-public-ip-id or --reserved-public-ip-name, not both")
+			}
+			if assignPublicIPFlag && (reservedPublicIPIDFlag != "" || reservedPublicIPNameFlag != "") {
+				return errors.New("--assign-public-ip cannot be combined with a reserved public IP")
+			}
+			if imageNameFlag != "" && osFlag != "" {
+				return errors.New("specify either --image-name or --os, not both")
+			}
+			if bootVolumeIDFlag != "" && (imageNameFlag != "" || osFlag != "") {
+				return errors.New("specify either an image (--image-name or --os) or --boot-volume-id, not both")
+			}
+			if imageNameFlag == "" && osFlag == "" && bootVolumeIDFlag == "" {
+				return errors.New("specify --image-name, --os or --boot-volume-id")
+			}
+			if bootVolumeSizeFlag < 0 {
+				return fmt.Errorf("invalid --boot-volume-size-in-gbs %d", bootVolumeSizeFlag)
+			}
+			if bootVolumeIDFlag != "" && bootVolumeSizeFlag != 0 {
+				return errors.New("--boot-volume-size-in-gbs cannot be used with --boot-volume-id (resize the boot volume instead)")
+			}
+			if bootVolumeIDFlag != "" && tryAllADsFlag {
+				return errors.New("--try-all-ads cannot be used with --boot-volume-id (a boot volume can only be attached in its own availability domain)")
+			}
+			if osVersionFlag != "" && osFlag == "" {
+				return errors.New("--os-version requires --os")
+			}
+			if dryRunFlag && validateOnlyFlag {
+				return errors.New("specify either --dry-run or --validate-only, not both")
+			}
+			if teardownOnFailureFlag && !waitFlag {
+				return errors.New("--teardown-on-failure requires --wait")
+			}
+			if (onReadyExecFlag != "" || waitForSSHFlag) && !waitFlag {
+				return errors.New("--on-ready-exec and --wait-for-ssh require --wait")
+			}
+
+			// 2. Setup Config Provider
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
 			}
 
 			// 3. Create Compute Client
 			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
 			if err != nil {
-				log.Fatalf("Error creating compute client: %v", err)
+				return fmt.Errorf("creating compute client: %w", err)
 			}
 
 			// 4. Resolve Compartment ID
@@ -122,31 +420,89 @@ func main() {
 			if compartmentInput != "" {
 				compartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
 				if err != nil {
-					log.Fatalf("Error resolving compartment ID '%s': %v", compartmentInput, err)
+					return fmt.Errorf("resolving compartment ID '%s': %w", compartmentInput, err)
 				}
 			} else {
 				compartmentID, err = configProvider.TenancyOCID()
 				if err != nil {
-					log.Fatalf("Error getting tenancy OCID: %v", err)
+					return fmt.Errorf("getting tenancy OCID: %w", err)
 				}
 			}
 			fmt.Printf("Using Compartment ID: %s\n", compartmentID)
 
+			// A boot volume can only be used in its own availability domain
+			var bootVolume core.BootVolume
+			if bootVolumeIDFlag != "" {
+				bootVolume, err = getAvailableBootVolume(configProvider, bootVolumeIDFlag)
+				if err != nil {
+					return err
+				}
+				volumeAD := stringValue(bootVolume.AvailabilityDomain)
+				if adFlag == "" {
+					adFlag = volumeAD
+					fmt.Printf("Using Availability Domain of the boot volume: %s\n", adFlag)
+				} else if adFlag != volumeAD {
+					return fmt.Errorf("boot volume %s is in availability domain %s, not %s", bootVolumeIDFlag, volumeAD, adFlag)
+				}
+			}
+
+			// Select the availability domain automatically when the region has only one
+			if adFlag == "" {
+				adNames, err := listAvailabilityDomainNames(configProvider, compartmentID)
+				if err != nil {
+					return err
+				}
+				if len(adNames) != 1 {
+					return fmt.Errorf("--availability-domain is required when the region has several availability domains: %s (see 'instances list-ads')", strings.Join(adNames, ", "))
+				}
+				adFlag = adNames[0]
+				fmt.Printf("Using Availability Domain: %s\n", adFlag)
+			}
+
 			// 5. Resolve Image ID
 			tenancyOCID, err := configProvider.TenancyOCID()
 			if err != nil {
-				log.Fatalf("Error getting tenancy OCID: %v", err)
+				return fmt.Errorf("getting tenancy OCID: %w", err)
 			}
-			imageID, err := resolveImageNameToID(imageNameFlag, compartmentID, tenancyOCID, computeClient)
-			if err != nil {
-				log.Fatalf("Error resolving image name '%s': %v", imageNameFlag, err)
+			var imageID string
+			if bootVolumeIDFlag != "" {
+				// Shape compatibility is checked against the image the boot volume was created from, if known
+				imageID = stringValue(bootVolume.ImageId)
+				fmt.Printf("Using Boot Volume ID: %s\n", bootVolumeIDFlag)
+			} else if osFlag != "" {
+				imageID, err = resolveNewestImageByOS(osFlag, osVersionFlag, shapeNameFlag, compartmentID, computeClient)
+				if err != nil {
+					return fmt.Errorf("resolving image for %s %s: %w", osFlag, osVersionFlag, err)
+				}
+			} else {
+				imageID, err = resolveImageNameToID(imageNameFlag, compartmentID, tenancyOCID, strictImageMatchFlag, computeClient)
+				if err != nil {
+					return fmt.Errorf("resolving image name '%s': %w", imageNameFlag, err)
+				}
+			}
+			if imageID != "" {
+				fmt.Printf("Using Image ID: %s\n", imageID)
+			}
+
+			// Resolve Subnet ID
+			var networkClient core.VirtualNetworkClient
+			subnetID := subnetIDFlag
+			if subnetNameFlag != "" {
+				networkClient, err = newVirtualNetworkClient(configProvider)
+				if err != nil {
+					return err
+				}
+				subnetID, err = resolveSubnetNameToID(subnetNameFlag, compartmentID, vcnIDFlag, networkClient)
+				if err != nil {
+					return fmt.Errorf("resolving subnet name '%s': %w", subnetNameFlag, err)
+				}
+				fmt.Printf("Using Subnet ID: %s\n", subnetID)
 			}
-			fmt.Printf("Using Image ID: %s\n", imageID)
 
 			// 6. Validate Shape Name (resolveShapeNameToID currently validates existence)
-			_, err = resolveShapeNameToID(shapeNameFlag, compartmentID, imageID, computeClient)
+			_, err = resolveShapeNameToID(shapeNameFlag, compartmentID, imageID, ocpusFlag, memoryInGBsFlag, computeClient)
 			if err != nil {
-				log.Fatalf("Error validating shape name '%s' for image '%s': %v", shapeNameFlag, imageID, err)
+				return fmt.Errorf("validating shape name '%s' for image '%s': %w", shapeNameFlag, imageID, err)
 			}
 			fmt.Printf("Using Shape Name: %s\n", shapeNameFlag)
 
@@ -158,32 +514,78 @@ func main() {
 			fmt.Printf("Instance Display Name: %s\n", displayName)
 
 			// 8. Prepare SSH Keys Metadata
-			keys := strings.Split(publicKeysFlag, ",")
-			sshKeysString := ""
-			for i, key := range keys {
-				trimmedKey := strings.TrimSpace(key)
-				if trimmedKey != "" {
-					sshKeysString += trimmedKey
-					if i < len(keys)-1 {
-						sshKeysString += "\n"
-					}
+			if defaultSSHKeyFlag {
+				defaultFiles, err := findDefaultPublicKeyFiles()
+				if err != nil {
+					return err
 				}
+				fmt.Printf("Using default SSH public keys: %s\n", strings.Join(defaultFiles, ", "))
+				publicKeyFileFlags = append(publicKeyFileFlags, defaultFiles...)
 			}
-			if sshKeysString == "" {
-				log.Fatalf("Error: No valid public SSH keys provided.")
+			publicKeys, err := collectPublicKeys(publicKeysFlag, publicKeyFileFlags)
+			if err != nil {
+				return err
 			}
+			sshKeysString := strings.Join(publicKeys, "\n")
 			metadata := map[string]string{"ssh_authorized_keys": sshKeysString}
+			if userDataFileFlag != "" {
+				userData, err := os.ReadFile(expandHome(userDataFileFlag))
+				if err != nil {
+					return fmt.Errorf("reading user data file '%s': %w", userDataFileFlag, err)
+				}
+				metadata["user_data"] = base64.StdEncoding.EncodeToString(userData)
+				if size := metadataSize(metadata); size > maxMetadataSize {
+					fmt.Printf("Warning: Instance metadata is %d bytes, over OCI's %d byte limit; the launch will likely be rejected.\n", size, maxMetadataSize)
+				}
+			}
 
 			// 9. Prepare VNIC Details
 			createVnicDetails := core.CreateVnicDetails{
-				SubnetId: &subnetIDFlag,
-				// AssignPublicIp: common.Bool(true), // Default is usually true, explicitly set if needed
+				SubnetId: &subnetID,
+			}
+			// Without --assign-public-ip the subnet's default applies
+			if cmd.Flags().Changed("assign-public-ip") {
+				createVnicDetails.AssignPublicIp = &assignPublicIPFlag
+			}
+
+			// A reserved public IP replaces the ephemeral one, so none may be assigned at launch
+			reservedPublicIPID := reservedPublicIPIDFlag
+			if reservedPublicIPIDFlag != "" || reservedPublicIPNameFlag != "" {
+				networkClient, err = newVirtualNetworkClient(configProvider)
+				if err != nil {
+					return err
+				}
+				if reservedPublicIPNameFlag != "" {
+					reservedPublicIPID, err = resolveReservedPublicIPNameToID(reservedPublicIPNameFlag, compartmentID, networkClient)
+					if err != nil {
+						return fmt.Errorf("resolving reserved public IP '%s': %w", reservedPublicIPNameFlag, err)
+					}
+				}
+				fmt.Printf("Using Reserved Public IP ID: %s\n", reservedPublicIPID)
+				createVnicDetails.AssignPublicIp = common.Bool(false)
 			}
 
 			// 10. Prepare Source Details
-			sourceDetails := core.InstanceSourceViaImageDetails{
+			imageSource := core.InstanceSourceViaImageDetails{
 				ImageId: &imageID,
 			}
+			if bootVolumeSizeFlag != 0 {
+				minimum, err := imageMinimumBootVolumeSizeInGBs(computeClient, imageID)
+				if err != nil {
+					return err
+				}
+				if bootVolumeSizeFlag < minimum {
+					return fmt.Errorf("--boot-volume-size-in-gbs %d is smaller than the image, which needs at least %d GB", bootVolumeSizeFlag, minimum)
+				}
+				imageSource.BootVolumeSizeInGBs = common.Int64(bootVolumeSizeFlag)
+				fmt.Printf("Boot Volume Size: %d GB\n", bootVolumeSizeFlag)
+			}
+			var sourceDetails core.InstanceSourceDetails = imageSource
+			if bootVolumeIDFlag != "" {
+				sourceDetails = core.InstanceSourceViaBootVolumeDetails{
+					BootVolumeId: &bootVolumeIDFlag,
+				}
+			}
 
 			// 11. Build Launch Instance Details
 			launchDetails := core.LaunchInstanceDetails{
@@ -194,6 +596,12 @@ func main() {
 				CreateVnicDetails:  &createVnicDetails,
 				SourceDetails:      sourceDetails,
 				Metadata:           metadata,
+				FreeformTags:       freeformTags,
+				DefinedTags:        definedTags,
+			}
+			// Without --fault-domain OCI picks the fault domain
+			if faultDomainFlag != "" {
+				launchDetails.FaultDomain = &faultDomainFlag
 			}
 
 			// Add shape config for Flex shapes
@@ -205,187 +613,375 @@ func main() {
 				launchDetails.ShapeConfig = &shapeConfig
 			}
 
+			// Select a burstable baseline if requested and supported by the shape
+			if baselineUtilizationFlag != "" {
+				baseline, err := resolveBaselineUtilization(computeClient, compartmentID, imageID, shapeNameFlag, baselineUtilizationFlag)
+				if err != nil {
+					return err
+				}
+				if launchDetails.ShapeConfig == nil {
+					launchDetails.ShapeConfig = &core.LaunchInstanceShapeConfigDetails{}
+				}
+				launchDetails.ShapeConfig.BaselineOcpuUtilization = baseline
+				fmt.Printf("Baseline OCPU Utilization: %s\n", baseline)
+			}
+
+			// Apply launch options from the image and/or explicit flags
+			var launchOptions *core.LaunchOptions
+			if autoLaunchOptionsFlag {
+				if imageID == "" {
+					return errors.New("--auto-launch-options needs the source image, which the boot volume does not record")
+				}
+				imageResponse, err := apiCall(computeClient.GetImage, core.GetImageRequest{ImageId: &imageID})
+				if err != nil {
+					return fmt.Errorf("getting image '%s' for launch options: %w", imageID, err)
+				}
+				if imageResponse.Image.LaunchOptions != nil {
+					imageOptions := *imageResponse.Image.LaunchOptions
+					launchOptions = &imageOptions
+					fmt.Printf("Auto-applied launch options from image: %s\n", describeLaunchOptions(launchOptions))
+				} else {
+					fmt.Println("Image does not specify launch options; using platform defaults.")
+				}
+			}
+			launchOptions, err = applyLaunchOptionOverrides(launchOptions, firmwareFlag, networkTypeFlag, bootVolumeTypeFlag, remoteDataVolumeTypeFlag)
+			if err != nil {
+				return err
+			}
+			if launchOptions != nil {
+				launchDetails.LaunchOptions = launchOptions
+				fmt.Printf("Using Launch Options: %s\n", describeLaunchOptions(launchOptions))
+			}
+
+			if capacityReservationIDFlag != "" {
+				launchDetails.CapacityReservationId = &capacityReservationIDFlag
+			}
+
+			// Print the resolved launch details without calling any mutating API
+			if dryRunFlag {
+				fmt.Println("Dry run: LaunchInstance would be called with these details:")
+				if err := printJSON(launchDetails); err != nil {
+					return fmt.Errorf("encoding launch details: %w", err)
+				}
+				return nil
+			}
+
+			// Validate against limits and capacity instead of launching
+			if validateOnlyFlag {
+				fmt.Println("Validating launch against service limits...")
+				ok, err := validateLaunch(configProvider, computeClient, tenancyOCID, launchRequirements{
+					CompartmentID:         compartmentID,
+					AvailabilityDomain:    adFlag,
+					Shape:                 shapeNameFlag,
+					ImageID:               imageID,
+					Ocpus:                 ocpusFlag,
+					MemoryInGBs:           memoryInGBsFlag,
+					CapacityReservationID: capacityReservationIDFlag,
+				})
+				if err != nil {
+					return fmt.Errorf("validating launch: %w", err)
+				}
+				if !ok {
+					return errors.New("validation failed: the launch is expected to fail")
+				}
+				fmt.Println("Validation passed: the launch is expected to succeed.")
+				return nil
+			}
+
 			// 12. Create Launch Request
 			request := core.LaunchInstanceRequest{
 				LaunchInstanceDetails: launchDetails,
+				OpcRetryToken:         common.String(retryToken(cmd)),
 			}
 
 			fmt.Println("Launching instance...")
 
-			// 13. Call API
-			response, err := computeClient.LaunchInstance(context.Background(), request)
+			// 13. Call API, following the placement plan
+			var allADs []string
+			if tryAllADsFlag {
+				allADs, err = listAvailabilityDomainNames(configProvider, tenancyOCID)
+				if err != nil {
+					return err
+				}
+			}
+			plan := placementPlan(adFlag, preferredADAttemptsFlag, tryAllADsFlag, allADs)
+			response, err := launchWithPlacement(computeClient, request, plan, time.Duration(placementRetryIntervalFlag)*time.Second)
 			if err != nil {
-				log.Fatalf("Error launching instance: %v", err)
+				return fmt.Errorf("launching instance: %w", err)
 			}
 
 			// 14. Print Result
 			fmt.Printf("Instance launch initiated successfully.\nInstance ID: %s\nState: %s\n", *response.Instance.Id, response.Instance.LifecycleState)
-			fmt.Println("Note: Instance provisioning takes time. Use 'instances info' to check status.")
+			if response.OpcWorkRequestId != nil {
+				fmt.Printf("Work Request ID: %s\n", *response.OpcWorkRequestId)
+			}
+			if tags := formatTags(response.Instance.FreeformTags, response.Instance.DefinedTags); len(tags) > 0 {
+				fmt.Printf("Tags: %s\n", strings.Join(tags, ", "))
+			}
+
+			// Attach the reserved public IP once the primary VNIC exists
+			if reservedPublicIPID != "" {
+				fmt.Println("Waiting for the primary VNIC to attach the reserved public IP...")
+				vnicID, err := waitForPrimaryVnic(computeClient, networkClient, compartmentID, *response.Instance.Id, time.Duration(waitTimeoutFlag)*time.Second)
+				if err != nil {
+					return err
+				}
+				address, err := assignReservedPublicIP(networkClient, reservedPublicIPID, vnicID)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Reserved public IP %s assigned to instance.\n", address)
+			}
+
+			if !waitFlag {
+				fmt.Println("Note: Instance provisioning takes time. Use 'instances info' to check status.")
+				return nil
+			}
+
+			// 15. Wait for the instance to become RUNNING
+			fmt.Println("Waiting for instance to reach RUNNING...")
+			waitStart := time.Now()
+			instance, err := waitForLaunch(configProvider, computeClient, response, time.Duration(waitTimeoutFlag)*time.Second)
+			if err != nil {
+				// Only tear down when OCI reported the launch as failed; after a timeout or an
+				// API error the instance may still be provisioning normally
+				if teardownOnFailureFlag && errors.Is(err, errProvisioningFailed) {
+					if teardownErr := teardownInstance(computeClient, *response.Instance.Id, bootVolumeIDFlag != ""); teardownErr != nil {
+						fmt.Printf("Error: Teardown failed: %v\n", teardownErr)
+					}
+				}
+				return fmt.Errorf("instance did not become RUNNING: %w", err)
+			}
+			fmt.Printf("Instance is %s (after %s).\n", instance.LifecycleState, time.Since(waitStart).Round(time.Second))
+
+			// 16. Print the assigned IPs
+			networkClient, err = newVirtualNetworkClient(configProvider)
+			if err != nil {
+				return err
+			}
+			vnics, err := resolveInstanceVnics(computeClient, networkClient, compartmentID, *instance.Id)
+			if err != nil {
+				return fmt.Errorf("resolving instance IPs: %w", err)
+			}
+			var publicIP, privateIP string
+			for _, vnic := range vnics {
+				if vnic.Primary {
+					publicIP, privateIP = vnic.PublicIP, vnic.PrivateIP
+				}
+			}
+			fmt.Printf("Private IP: %s\nPublic IP: %s\n", privateIP, publicIP)
+
+			// 17. Run the on-ready hooks
+			if waitForSSHFlag {
+				sshHost := publicIP
+				if sshHost == "" {
+					sshHost = privateIP
+				}
+				fmt.Printf("Waiting for SSH on %s...\n", sshHost)
+				if err := waitForSSH(sshHost, time.Duration(waitTimeoutFlag)*time.Second); err != nil {
+					return err
+				}
+			}
+
+			if onReadyExecFlag != "" {
+				fmt.Printf("Running on-ready command: %s\n", onReadyExecFlag)
+				exitCode, err := runOnReadyCommand(onReadyExecFlag, *instance.Id, publicIP, privateIP)
+				if err != nil {
+					return err
+				}
+				if exitCode != 0 {
+					return &exitCodeError{code: exitCode, err: fmt.Errorf("on-ready command exited with code %d", exitCode)}
+				}
+			}
 
+			return nil
 		},
 	}
 	// Add flags needed for instance creation
 	createCmd.Flags().String("name", "", "(Optional) Display name for the new instance (auto-generated if empty)")
-	createCmd.Flags().String("compartment-id", "", "(Optional) OCID or name of the compartment to create instance in (defaults to tenancy root)")
+	createCmd.Flags().String("compartment-id", "", "(Optional) OCID, name or path of the compartment to create instance in (defaults to tenancy root)")
 	createCmd.Flags().String("shape-name", "", "Shape name for the new instance (e.g., VM.Standard.A1.Flex) (Required)")
-	createCmd.Flags().String("image-name", "", "Display name of the OS image (e.g., 'Canonical Ubuntu 24.04 Minimal aarch64') (Required)")
-	createCmd.Flags().String("subnet-id", "", "OCID of the subnet for the instance's VNIC (Required)")
-	createCmd.Flags().String("availability-domain", "", "Availability Domain name (e.g., 'Uocm:US-ASHBURN-AD-1') (Required)")
-	createCmd.Flags().String("public-keys", "", "Comma-separated list of public SSH keys (Required)")
+	createCmd.Flags().String("image-name", "", "Display name of the OS image (e.g., 'Canonical Ubuntu 24.04 Minimal aarch64') (Required unless --os or --boot-volume-id is given)")
+	createCmd.Flags().String("boot-volume-id", "", "(Optional) OCID of an existing boot volume to launch from instead of an image (e.g. one preserved at termination)")
+	createCmd.Flags().Int64("boot-volume-size-in-gbs", 0, "(Optional) Size of the boot volume in GB instead of the image's default; must be at least the image size")
+	createCmd.Flags().Bool("strict-image-match", false, "(Optional) Fail instead of using the first image when several images share --image-name")
+	createCmd.Flags().String("os", "", "(Optional) Operating system of the image instead of --image-name (e.g., 'Canonical Ubuntu'); the newest image compatible with the shape is used")
+	createCmd.Flags().String("os-version", "", "(Optional, requires --os) Operating system version of the image (e.g., '24.04')")
+	createCmd.Flags().String("subnet-id", "", "OCID of the subnet for the instance's VNIC (Required unless --subnet-name is given)")
+	createCmd.Flags().String("subnet-name", "", "(Optional) Display name of the subnet, looked up in --compartment-id, instead of --subnet-id")
+	createCmd.Flags().String("vcn-id", "", "(Optional) OCID of the VCN used to disambiguate --subnet-name")
+	createCmd.Flags().String("availability-domain", "", "(Optional) Availability Domain name (e.g., 'Uocm:US-ASHBURN-AD-1'); selected automatically if the region has only one")
+	createCmd.Flags().String("preferred-ad", "", "(Optional) Availability Domain to try first, retried up to --preferred-ad-attempts times when out of capacity")
+	createCmd.Flags().Int("preferred-ad-attempts", 3, "(Optional) Number of launch attempts in --preferred-ad before giving up or falling back")
+	createCmd.Flags().String("fault-domain", "", "(Optional) Fault domain to place the instance in, e.g. FAULT-DOMAIN-1 (see 'instances list-fault-domains')")
+	createCmd.Flags().Bool("try-all-ads", false, "(Optional) When out of capacity, fall back to the other Availability Domains (requires a regional subnet)")
+	createCmd.Flags().Int("placement-retry-interval", 30, "(Optional) Seconds to wait between attempts in the same Availability Domain")
+	createCmd.Flags().String("public-keys", "", "Comma-separated list of public SSH keys (Required unless --public-key-file or --default-ssh-key is given)")
+	createCmd.Flags().StringArray("public-key-file", nil, "(Optional, repeatable) File of public SSH keys, one per line, to add to the instance")
+	createCmd.Flags().Bool("default-ssh-key", false, "(Optional) Also add ~/.ssh/id_ed25519.pub and ~/.ssh/id_rsa.pub, whichever exist")
+	createCmd.Flags().String("user-data-file", "", "(Optional) cloud-init user data file to run on first boot")
 	createCmd.Flags().Float32("ocpus", 0, "(Required for Flex shapes) Number of OCPUs")
 	createCmd.Flags().Float32("memory-in-gbs", 0, "(Optional for Flex shapes) Amount of memory in GB")
+	createCmd.Flags().String("baseline-utilization", "", "(Optional) Burstable baseline OCPU utilization (BASELINE_1_8, BASELINE_1_2 or BASELINE_1_1); the shape must support it")
+	createCmd.Flags().Bool("auto-launch-options", false, "(Optional) Apply the launch options recommended by the selected image")
+	createCmd.Flags().String("firmware", "", "(Optional) Firmware launch option (BIOS or UEFI_64); overrides --auto-launch-options")
+	createCmd.Flags().String("network-type", "", "(Optional) NIC emulation launch option (E1000, VFIO or PARAVIRTUALIZED); overrides --auto-launch-options")
+	createCmd.Flags().String("boot-volume-type", "", "(Optional) Boot volume launch option (ISCSI, SCSI, IDE, VFIO or PARAVIRTUALIZED); overrides --auto-launch-options")
+	createCmd.Flags().String("remote-data-volume-type", "", "(Optional) Data volume launch option (ISCSI, SCSI, IDE, VFIO or PARAVIRTUALIZED); overrides --auto-launch-options")
+	createCmd.Flags().Bool("assign-public-ip", false, "(Optional) Whether to assign an ephemeral public IP (--assign-public-ip=false for private subnets); defaults to the subnet's setting")
+	createCmd.Flags().String("reserved-public-ip-id", "", "(Optional) OCID of a reserved public IP to assign instead of an ephemeral one")
+	createCmd.Flags().String("reserved-public-ip-name", "", "(Optional) Display name of a reserved public IP to assign instead of an ephemeral one")
+	createCmd.Flags().Bool("wait", false, "(Optional) Wait until the instance is RUNNING before exiting")
+	createCmd.Flags().Int("wait-timeout", 600, "(Optional) Maximum number of seconds to wait with --wait")
+	createCmd.Flags().String("on-ready-exec", "", "(Optional, requires --wait) Local shell command to run once the instance is RUNNING; gets OCI_INSTANCE_ID, OCI_INSTANCE_PUBLIC_IP and OCI_INSTANCE_PRIVATE_IP")
+	createCmd.Flags().Bool("wait-for-ssh", false, "(Optional, requires --wait) Also wait until port 22 of the instance accepts connections")
+	createCmd.Flags().Bool("teardown-on-failure", false, "(Optional, requires --wait) Terminate the instance and delete its boot volume if OCI reports provisioning as failed (not on a wait timeout); a volume given with --boot-volume-id is preserved")
+	createCmd.Flags().StringArray("freeform-tag", nil, "(Optional, repeatable) Freeform tag to apply, as key=value")
+	createCmd.Flags().StringArray("defined-tag", nil, "(Optional, repeatable) Defined tag to apply, as namespace.key=value")
+	createCmd.Flags().String("capacity-reservation-id", "", "(Optional) OCID of the compute capacity reservation to launch into")
+	addRetryTokenFlag(createCmd)
+	createCmd.Flags().Bool("dry-run", false, "(Optional) Resolve all inputs and print the launch details as JSON without launching")
+	createCmd.Flags().Bool("validate-only", false, "(Optional) Resolve inputs and check service limits/capacity without launching; exits non-zero if the launch would fail")
 	// Mark required flags
 	_ = createCmd.MarkFlagRequired("shape-name")
-	_ = createCmd.MarkFlagRequired("image-name")
-	_ = createCmd.MarkFlagRequired("subnet-id")
-	_ = createCmd.MarkFlagRequired("availability-domain")
-	_ = createCmd.MarkFlagRequired("public-keys")
 
 	var infoCmd = &cobra.Command{
 		Use:   "info",
 		Short: "Show information about a compute instance",
-		PreRun: func(cmd *cobra.Command, args []string) {
-			fmt.Println("Debug: About to run instances info command")
-		},
-		Run: func(cmd *cobra.Command, args []string) {
-			idFlag, _ := cmd.Flags().GetString("id")
-			nameFlag, _ := cmd.Flags().GetString("name")
-			compartmentFlag, _ := cmd.Flags().GetString("compartment-id")
-			profileFlag, _ := cmd.Flags().GetString("profile")
-			var configProvider common.ConfigurationProvider
-			var err error
+		RunE: func(cmd *cobra.Command, args []string) error {
+			followFlag, _ := cmd.Flags().GetBool("follow")
+			pollIntervalFlag, _ := cmd.Flags().GetInt("poll-interval")
+			fieldsFlag, _ := cmd.Flags().GetString("fields")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+			if followFlag && isStructuredOutput(output) {
+				return errors.New("--follow is only supported with --output text")
+			}
+			fields, err := parseInstanceFields(fieldsFlag)
+			if err != nil {
+				return err
+			}
+			if followFlag && len(fields) > 0 {
+				return errors.New("--fields is not supported with --follow")
+			}
 
-			if profileFlag != "" {
-				configProvider = common.CustomProfileConfigProvider("~/.oci/config", profileFlag)
-			} else {
-				configProvider = common.DefaultConfigProvider()
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
 			}
 
-			if idFlag != "" && nameFlag != "" {
-				fmt.Println("Error: Specify either --id or --name, not both.")
-				os.Exit(1)
-			} else if idFlag != "" {
-				computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
-				if err != nil {
-					fmt.Printf("Error: Creating compute client failed: %v\n", err)
-					os.Exit(1)
-				}
-				request := core.GetInstanceRequest{InstanceId: &idFlag}
-				response, err := computeClient.GetInstance(context.Background(), request)
-				if err != nil {
-					fmt.Printf("Error: Getting instance by ID failed: %v\n", err)
-					os.Exit(1)
-				}
-				displayInstanceDetails(&response.Instance)
-			} else if nameFlag != "" {
-				var compartmentID string
-				if compartmentFlag == "" {
-					tenancyOCID, err := configProvider.TenancyOCID()
-					if err != nil {
-						fmt.Printf("Error: Getting tenancy OCID failed: %v\n", err)
-						os.Exit(1)
-					}
-					compartmentID = tenancyOCID
-				} else {
-					compartmentID, err = resolveCompartmentID(compartmentFlag, configProvider)
-					if err != nil {
-						fmt.Printf("Error: Resolving compartment ID failed: %v\n", err)
-						os.Exit(1)
-					}
-				}
+			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating compute client failed: %w", err)
+			}
 
-				computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
-				if err != nil {
-					fmt.Printf("Error: Creating compute client failed: %v\n", err)
-					os.Exit(1)
-				}
-				listRequest := core.ListInstancesRequest{CompartmentId: &compartmentID}
-				listResponse, err := computeClient.ListInstances(context.Background(), listRequest)
-				if err != nil {
-					fmt.Printf("Error: Listing instances failed: %v\n", err)
-					os.Exit(1)
-				}
-				found := false
-				for _, instanceSummary := range listResponse.Items {
-					if *instanceSummary.DisplayName == nameFlag {
-						getRequest := core.GetInstanceRequest{InstanceId: instanceSummary.Id}
-						fullResponse, err := computeClient.GetInstance(context.Background(), getRequest)
-						if err != nil {
-							fmt.Printf("Error: Getting full instance details failed: %v\n", err)
-							os.Exit(1)
-						}
-						displayInstanceDetails(&fullResponse.Instance)
-						found = true
-						break
+			instanceID, err := resolveInstanceSelector(cmd, configProvider, computeClient)
+			if err != nil {
+				return err
+			}
+
+			request := core.GetInstanceRequest{InstanceId: &instanceID}
+			response, err := getInstance(computeClient, request)
+			if err != nil {
+				return fmt.Errorf("getting instance by ID failed: %w", err)
+			}
+
+			networkClient, err := newVirtualNetworkClient(configProvider)
+			if err != nil {
+				return err
+			}
+			vnics, err := resolveInstanceVnics(computeClient, networkClient, *response.Instance.CompartmentId, instanceID)
+			if err != nil {
+				return fmt.Errorf("resolving instance IPs failed: %w", err)
+			}
+
+			if len(fields) > 0 {
+				record := instanceRecord{instance: response.Instance, vnics: vnics}
+				if isStructuredOutput(output) {
+					if err := printStructured(output, projectInstance(fields, record)); err != nil {
+						return fmt.Errorf("encoding output failed: %w", err)
 					}
+					return nil
 				}
-				if !found {
-					fmt.Println("No instance found with that display name in the compartment.")
+				printInstanceFieldsTable(cmd, fields, []instanceRecord{record})
+				return nil
+			}
+			if isStructuredOutput(output) {
+				details := newInstanceWithVnicsJSON(newInstanceJSON(&response.Instance), vnics)
+				if err := printStructured(output, details); err != nil {
+					return fmt.Errorf("encoding output failed: %w", err)
 				}
-			} else {
-				fmt.Println("Error: Specify either --id or --name.")
-				os.Exit(1)
+				return nil
 			}
+			displayInstanceDetails(&response.Instance, vnics)
+			if followFlag {
+				return followInstance(computeClient, networkClient, response.Instance, time.Duration(pollIntervalFlag)*time.Second)
+			}
+			return nil
 		},
 	}
 
-	infoCmd.Flags().String("id", "", "The OCID of the instance to get info for")
-	infoCmd.Flags().String("name", "", "The display name of the instance to search for")
-	infoCmd.Flags().String("compartment-id", "", "The OCID or friendly name of the compartment (optional, defaults to tenancy if not specified)")
+	addInstanceSelectorFlags(infoCmd, "get info for")
+	infoCmd.Flags().Bool("follow", false, "Keep refreshing the details until the instance reaches RUNNING, STOPPED or TERMINATED")
+	infoCmd.Flags().Int("poll-interval", 5, "Seconds between refreshes with --follow")
+	addFieldsFlag(infoCmd)
+	addNoTruncateFlag(infoCmd)
 
 	// Define list-images command
 	var listImagesCmd = &cobra.Command{
 		Use:   "list-images",
 		Short: "List available compute images (custom or platform)",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			// 1. Get Flags
-			profileFlag, _ := cmd.Flags().GetString("profile")
 			compartmentInput, _ := cmd.Flags().GetString("compartment-id")
 			platformFlag, _ := cmd.Flags().GetBool("platform")
 			osFilter, _ := cmd.Flags().GetString("os")
-			limitFlag, _ := cmd.Flags().GetInt("limit")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
 
 			// 2. Setup Config Provider
-			var configProvider common.ConfigurationProvider
-			if profileFlag != "" {
-				configProvider = common.CustomProfileConfigProvider("~/.oci/config", profileFlag)
-			} else {
-				configProvider = common.DefaultConfigProvider()
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
 			}
 
 			// 3. Create Compute Client
 			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
 			if err != nil {
-				log.Fatalf("Error creating compute client: %v", err)
+				return fmt.Errorf("creating compute client: %w", err)
 			}
 
 			// 4. Determine Compartment ID for Query
 			tenancyOCID, err := configProvider.TenancyOCID()
 			if err != nil {
-				log.Fatalf("Error getting tenancy OCID: %v", err)
+				return fmt.Errorf("getting tenancy OCID: %w", err)
 			}
 
 			var queryCompartmentID string
 			if platformFlag {
 				// Platform images are typically queried against the tenancy OCID
 				queryCompartmentID = tenancyOCID
-				fmt.Println("Listing platform images...")
+				progress(output, "Listing platform images...")
 			} else if compartmentInput != "" {
 				queryCompartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
 				if err != nil {
-					log.Fatalf("Error resolving compartment ID '%s': %v", compartmentInput, err)
+					return fmt.Errorf("resolving compartment ID '%s': %w", compartmentInput, err)
 				}
-				fmt.Printf("Listing images in compartment: %s\n", queryCompartmentID)
+				progress(output, "Listing images in compartment: %s", queryCompartmentID)
 			} else {
 				// Default to listing custom images in the tenancy root if no specific compartment or platform flag is given
 				queryCompartmentID = tenancyOCID
-				fmt.Printf("Listing images in tenancy root: %s\n", queryCompartmentID)
+				progress(output, "Listing images in tenancy root: %s", queryCompartmentID)
 			}
 
 			// 5. Build ListImages Request
 			request := core.ListImagesRequest{
 				CompartmentId: &queryCompartmentID,
-				Limit:         common.Int(limitFlag),
+				Limit:         common.Int(pageSize),
 				SortBy:        core.ListImagesSortByTimecreated,
 				SortOrder:     core.ListImagesSortOrderDesc,
 			}
@@ -393,65 +989,86 @@ func main() {
 				request.OperatingSystem = &osFilter
 			}
 
-			fmt.Println("Fetching images...")
+			progress(output, "Fetching images...")
 
 			// 6. Call API
-			response, err := computeClient.ListImages(context.Background(), request)
+			images, truncated, err := collectPages(maxItems(cmd), func(page *string) ([]core.Image, *string, error) {
+				request.Page = page
+				response, err := listImages(computeClient, request)
+				return response.Items, response.OpcNextPage, err
+			})
 			if err != nil {
-				log.Fatalf("Error listing images: %v", err)
+				return fmt.Errorf("listing images: %w", err)
 			}
 
 			// 7. Print Results
-			if len(response.Items) == 0 {
+			if isStructuredOutput(output) {
+				region, _ := configProvider.Region()
+				meta := listMeta{Count: len(images), Truncated: truncated, Region: region}
+				if err := printList(output, images, meta); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			if len(images) == 0 {
 				fmt.Println("No images found matching the criteria.")
-				return
+				return nil
 			}
 
-			fmt.Printf("Found %d images:\n", len(response.Items))
-			fmt.Println("--------------------------------------------------")
-			for _, image := range response.Items {
-				fmt.Printf("Display Name: %s\n", *image.DisplayName)
-				fmt.Printf("  ID:           %s\n", *image.Id)
-				fmt.Printf("  OS:           %s\n", *image.OperatingSystem)
+			table := newTextTable(cmd,
+				tableColumn{header: "DISPLAY NAME"},
+				tableColumn{header: "ID", ocid: true},
+				tableColumn{header: "OS"},
+				tableColumn{header: "OS VERSION"},
+				tableColumn{header: "BASE IMAGE", ocid: true},
+				tableColumn{header: "STATE"},
+			)
+			for _, image := range images {
+				baseImage := "-"
 				if image.BaseImageId != nil {
-					fmt.Printf("  Base Image:   %s\n", *image.BaseImageId)
+					baseImage = *image.BaseImageId
 				}
-				fmt.Printf("  State:        %s\n", image.LifecycleState)
-				fmt.Println("--------------------------------------------------")
+				table.addRow(stringValue(image.DisplayName), stringValue(image.Id), stringValue(image.OperatingSystem), stringValue(image.OperatingSystemVersion), baseImage, string(image.LifecycleState))
 			}
+			table.print()
+			printListFooter(len(images), truncated, "images")
+			return nil
 		},
 	}
 
 	// Add flags to list-images command
-	listImagesCmd.Flags().String("compartment-id", "", "(Optional) OCID or name of the compartment to list custom images from (defaults to tenancy root)")
+	listImagesCmd.Flags().String("compartment-id", "", "(Optional) OCID, name or path of the compartment to list custom images from (defaults to tenancy root)")
 	listImagesCmd.Flags().Bool("platform", false, "List only platform images (ignores compartment-id)")
 	listImagesCmd.Flags().String("os", "", "(Optional) Filter by operating system name (e.g., 'Oracle Linux', 'Ubuntu')")
-	listImagesCmd.Flags().Int("limit", 50, "(Optional) Limit the number of results returned")
+	addMaxItemsFlag(listImagesCmd)
+	addNoTruncateFlag(listImagesCmd)
 
 	// Define list-shapes command
 	var listShapesCmd = &cobra.Command{
 		Use:   "list-shapes",
 		Short: "List available compute shapes for a compartment",
 		Long:  `Lists compute shapes available in a specific compartment, optionally filtered by a specific image ID.`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			// 1. Get Flags
-			profileFlag, _ := cmd.Flags().GetString("profile")
 			compartmentInput, _ := cmd.Flags().GetString("compartment-id")
 			imageIDFlag, _ := cmd.Flags().GetString("image-id")
-			limitFlag, _ := cmd.Flags().GetInt("limit")
+			flexOnlyFlag, _ := cmd.Flags().GetBool("flex-only")
+			containsFlag, _ := cmd.Flags().GetString("contains")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
 
 			// 2. Setup Config Provider
-			var configProvider common.ConfigurationProvider
-			if profileFlag != "" {
-				configProvider = common.CustomProfileConfigProvider("~/.oci/config", profileFlag)
-			} else {
-				configProvider = common.DefaultConfigProvider()
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
 			}
 
 			// 3. Create Compute Client
 			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
 			if err != nil {
-				log.Fatalf("Error creating compute client: %v", err)
+				return fmt.Errorf("creating compute client: %w", err)
 			}
 
 			// 4. Resolve Compartment ID
@@ -459,66 +1076,105 @@ func main() {
 			if compartmentInput != "" {
 				compartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
 				if err != nil {
-					log.Fatalf("Error resolving compartment ID '%s': %v", compartmentInput, err)
+					return fmt.Errorf("resolving compartment ID '%s': %w", compartmentInput, err)
 				}
 			} else {
 				compartmentID, err = configProvider.TenancyOCID()
 				if err != nil {
-					log.Fatalf("Error getting tenancy OCID: %v", err)
+					return fmt.Errorf("getting tenancy OCID: %w", err)
 				}
 			}
 
 			// 5. Build ListShapes Request
 			request := core.ListShapesRequest{
 				CompartmentId: &compartmentID,
-				Limit:         common.Int(limitFlag),
+				Limit:         common.Int(pageSize),
 			}
 			if imageIDFlag != "" {
 				request.ImageId = &imageIDFlag
 			}
 
-			fmt.Println("Fetching shapes...")
+			progress(output, "Fetching shapes...")
 
 			// 6. Call API
-			response, err := computeClient.ListShapes(context.Background(), request)
+			// The name and Flex filters are applied client-side, so the cap is applied after filtering
+			limit := maxItems(cmd)
+			filtered := flexOnlyFlag || containsFlag != ""
+			fetchLimit := limit
+			if filtered {
+				fetchLimit = 0
+			}
+			shapes, truncated, err := collectPages(fetchLimit, func(page *string) ([]core.Shape, *string, error) {
+				request.Page = page
+				response, err := listShapes(computeClient, request)
+				return response.Items, response.OpcNextPage, err
+			})
 			if err != nil {
-				log.Fatalf("Error listing shapes: %v", err)
+				return fmt.Errorf("listing shapes: %w", err)
+			}
+			if filtered {
+				shapes = filterShapes(shapes, flexOnlyFlag, containsFlag)
+				if limit > 0 && len(shapes) > limit {
+					shapes = shapes[:limit]
+					truncated = true
+				}
 			}
 
 			// 7. Print Results
-			if len(response.Items) == 0 {
+			if isStructuredOutput(output) {
+				region, _ := configProvider.Region()
+				meta := listMeta{Count: len(shapes), Truncated: truncated, Region: region}
+				if err := printList(output, shapes, meta); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			if len(shapes) == 0 {
 				fmt.Println("No shapes found matching the criteria.")
-				return
+				return nil
 			}
 
-			fmt.Printf("Found %d shapes:\n", len(response.Items))
-			fmt.Println("--------------------------------------------------")
-			for _, shape := range response.Items {
-				fmt.Printf("Shape Name: %s\n", *shape.Shape)
-				if shape.ProcessorDescription != nil {
-					fmt.Printf("  Processor:  %s\n", *shape.ProcessorDescription)
-				}
+			table := newTextTable(cmd,
+				tableColumn{header: "SHAPE"},
+				tableColumn{header: "PROCESSOR"},
+				tableColumn{header: "OCPUS", numeric: true},
+				tableColumn{header: "MEMORY (GB)", numeric: true},
+				tableColumn{header: "NET BW (GBPS)", numeric: true},
+			)
+			for _, shape := range shapes {
+				ocpus, memory, bandwidth := "-", "-", "-"
 				if shape.OcpuOptions != nil {
-					fmt.Printf("  OCPUs:      Min=%.2f, Max=%.2f\n", *shape.OcpuOptions.Min, *shape.OcpuOptions.Max) // Commenting out Default for now: , *shape.OcpuOptions.DefaultPerOcpu)
+					ocpus = fmt.Sprintf("%g-%g", *shape.OcpuOptions.Min, *shape.OcpuOptions.Max)
+				} else if shape.Ocpus != nil {
+					ocpus = fmt.Sprintf("%g", *shape.Ocpus)
 				}
 				if shape.MemoryOptions != nil {
-					fmt.Printf("  Memory (GB):Min=%.1f, Max=%.1f, Default=%.1f\n", *shape.MemoryOptions.MinInGBs, *shape.MemoryOptions.MaxInGBs, *shape.MemoryOptions.DefaultPerOcpuInGBs)
+					memory = fmt.Sprintf("%g-%g", *shape.MemoryOptions.MinInGBs, *shape.MemoryOptions.MaxInGBs)
+				} else if shape.MemoryInGBs != nil {
+					memory = fmt.Sprintf("%g", *shape.MemoryInGBs)
 				}
 				if shape.NetworkingBandwidthOptions != nil {
-				    fmt.Printf("  Net BW(Gbps):Min=%.1f, Max=%.1f, Default=%.1f\n", *shape.NetworkingBandwidthOptions.MinInGbps, *shape.NetworkingBandwidthOptions.MaxInGbps, *shape.NetworkingBandwidthOptions.DefaultPerOcpuInGbps)
+					bandwidth = fmt.Sprintf("%g-%g", *shape.NetworkingBandwidthOptions.MinInGbps, *shape.NetworkingBandwidthOptions.MaxInGbps)
+				} else if shape.NetworkingBandwidthInGbps != nil {
+					bandwidth = fmt.Sprintf("%g", *shape.NetworkingBandwidthInGbps)
 				}
-				// Print other relevant fields if needed
-				fmt.Println("--------------------------------------------------")
+				table.addRow(stringValue(shape.Shape), stringValue(shape.ProcessorDescription), ocpus, memory, bandwidth)
 			}
+			table.print()
+			printListFooter(len(shapes), truncated, "shapes")
+			return nil
 		},
 	}
 
 	// Add flags to list-shapes command
-	listShapesCmd.Flags().String("compartment-id", "", "(Optional) OCID or name of the compartment (defaults to tenancy root)")
+	listShapesCmd.Flags().String("compartment-id", "", "(Optional) OCID, name or path of the compartment (defaults to tenancy root)")
 	listShapesCmd.Flags().String("image-id", "", "(Optional) Filter shapes compatible with a specific image OCID")
-	listShapesCmd.Flags().Int("limit", 100, "(Optional) Limit the number of results returned")
+	listShapesCmd.Flags().Bool("flex-only", false, "(Optional) Only list Flex shapes (shapes with configurable OCPUs)")
+	listShapesCmd.Flags().String("contains", "", "(Optional) Only list shapes whose name contains this text (case-insensitive)")
+	addMaxItemsFlag(listShapesCmd)
+	addNoTruncateFlag(listShapesCmd)
 
-	instancesCmd.AddCommand(listCmd, createCmd, infoCmd, listImagesCmd, listShapesCmd)
+	instancesCmd.AddCommand(listCmd, createCmd, infoCmd, listImagesCmd, listShapesCmd, newStartCmd(), newStopCmd(), newRebootCmd(), newUpdateCmd(), newRenameCmd(), newTerminateCmd(), newRunScheduledCmd(), newExportInventoryCmd(), newListADsCmd(), newListFaultDomainsCmd(), newListVnicAttachmentsCmd(), newConsoleConnectionCmd(), newGetConsoleHistoryCmd())
 
 	// --- Compartments Commands --- 
 	var compartmentsCmd = &cobra.Command{
@@ -529,45 +1185,133 @@ func main() {
 	var listCompartmentsCmd = &cobra.Command{
 		Use:   "list",
 		Short: "List all compartments in the tenancy",
-		Run: func(cmd *cobra.Command, args []string) {
-			profileFlag, _ := cmd.Flags().GetString("profile")
-			var configProvider common.ConfigurationProvider
+		RunE: func(cmd *cobra.Command, args []string) error {
+			treeFlag, _ := cmd.Flags().GetBool("tree")
+			flatFlag, _ := cmd.Flags().GetBool("flat")
+			lifecycleStateFlag, _ := cmd.Flags().GetString("lifecycle-state")
 			var err error
+			var lifecycleState identity.CompartmentLifecycleStateEnum
+			if !strings.EqualFold(lifecycleStateFlag, "all") {
+				var ok bool
+				lifecycleState, ok = identity.GetMappingCompartmentLifecycleStateEnum(lifecycleStateFlag)
+				if !ok {
+					return fmt.Errorf("invalid --lifecycle-state '%s' (must be ALL or one of %s)", lifecycleStateFlag, strings.Join(identity.GetCompartmentLifecycleStateEnumStringValues(), ", "))
+				}
+			}
+			if treeFlag && flatFlag {
+				return errors.New("specify either --tree or --flat, not both")
+			}
 
-			if profileFlag != "" {
-				configProvider = common.CustomProfileConfigProvider("~/.oci/config", profileFlag)
-			} else {
-				configProvider = common.DefaultConfigProvider()
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
 			}
 
 			tenancyOCID, err := configProvider.TenancyOCID()
 			if err != nil {
-				fmt.Printf("Error getting tenancy OCID: %v\n", err)
-				os.Exit(1)
+				return fmt.Errorf("getting tenancy OCID: %w", err)
 			}
 
 			identityClient, err := identity.NewIdentityClientWithConfigurationProvider(configProvider)
 			if err != nil {
-				fmt.Printf("Error creating identity client: %v\n", err)
-				os.Exit(1)
+				return fmt.Errorf("creating identity client: %w", err)
+			}
+
+			if isStructuredOutput(output) {
+				compartments, err := listSubtreeCompartments(identityClient, tenancyOCID)
+				if err != nil {
+					return err
+				}
+				compartments = filterCompartmentsByState(compartments, lifecycleState)
+
+				var result interface{}
+				if treeFlag && output != "csv" {
+					result = buildCompartmentTree(compartments, tenancyOCID)
+				} else {
+					flat := make([]*compartmentJSON, len(compartments))
+					for i, compartment := range compartments {
+						flat[i] = newCompartmentJSON(compartment)
+					}
+					result = flat
+				}
+				region, _ := configProvider.Region()
+				if err := printList(output, result, listMeta{Count: len(compartments), Region: region}); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+
+			if flatFlag {
+				compartments, err := listSubtreeCompartments(identityClient, tenancyOCID)
+				if err != nil {
+					return err
+				}
+				paths := compartmentPaths(compartments, tenancyOCID)
+				compartments = filterCompartmentsByState(compartments, lifecycleState)
+				sort.Slice(compartments, func(i, j int) bool { return paths[*compartments[i].Id] < paths[*compartments[j].Id] })
+				table := newTextTable(cmd,
+					tableColumn{header: "PATH"},
+					tableColumn{header: "ID", ocid: true},
+					tableColumn{header: "STATE"},
+					tableColumn{header: "DESCRIPTION"},
+				)
+				for _, compartment := range compartments {
+					table.addRow(paths[*compartment.Id], stringValue(compartment.Id), string(compartment.LifecycleState), stringValue(compartment.Description))
+				}
+				table.print()
+				return nil
 			}
 
 			request := identity.ListCompartmentsRequest{
-				CompartmentId: &tenancyOCID,
+				CompartmentId:  &tenancyOCID,
+				LifecycleState: lifecycleState,
 			}
 
-			err = listCompartmentsRecursive(identityClient, &request, 0)
+			table := newTextTable(cmd,
+				tableColumn{header: "NAME"},
+				tableColumn{header: "ID", ocid: true},
+				tableColumn{header: "STATE"},
+				tableColumn{header: "DESCRIPTION"},
+			)
+			err = listCompartmentsRecursive(identityClient, &request, 0, table)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
+			table.print()
+			return nil
 		},
 	}
 
-	compartmentsCmd.AddCommand(listCompartmentsCmd)
+	addNoTruncateFlag(listCompartmentsCmd)
+	listCompartmentsCmd.Flags().Bool("tree", false, "With --output json or yaml, nest child compartments under their parents instead of a flat array")
+	listCompartmentsCmd.Flags().String("lifecycle-state", "ACTIVE", "Only list compartments in this state (ACTIVE, CREATING, INACTIVE, DELETING, DELETED), or ALL")
+	listCompartmentsCmd.Flags().Bool("flat", false, "Fetch the whole compartment subtree in one paginated query and print it as a flat list of paths (much faster for large tenancies)")
 
-	rootCmd.AddCommand(instancesCmd, compartmentsCmd)
+	compartmentsCmd.AddCommand(listCompartmentsCmd, newCreateCompartmentCmd(), newDeleteCompartmentCmd())
 
-	rootCmd.Execute()
+	rootCmd.AddCommand(instancesCmd, compartmentsCmd, newVolumesCmd(), newIdentityCmd(), newNetworkCmd(), newObjectStorageCmd(), newWorkRequestsCmd(), newConfigCmd(), newCompletionCmd())
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	registerFlagCompletions(rootCmd)
+
+	cancelOnInterrupt()
+	if err := rootCmd.ExecuteContext(rootContext); err != nil {
+		if errors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, cancelledByUserMessage)
+			os.Exit(130)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", serviceErrorMessage(err))
+		exitCode := 1
+		var codeErr *exitCodeError
+		if errors.As(err, &codeErr) {
+			exitCode = codeErr.code
+		}
+		os.Exit(exitCode)
+	}
 }
 
 func resolveCompartmentID(input string, configProvider common.ConfigurationProvider) (string, error) {
@@ -577,7 +1321,7 @@ func resolveCompartmentID(input string, configProvider common.ConfigurationProvi
 		return input, nil
 	}
 
-	// Input is likely a name, try to resolve it
+	// Input is likely a name or a path, try to resolve it
 	tenancyOCID, err := configProvider.TenancyOCID()
 	if err != nil {
 		return "", fmt.Errorf("failed to get tenancy OCID: %w", err)
@@ -587,78 +1331,474 @@ func resolveCompartmentID(input string, configProvider common.ConfigurationProvi
 	if err != nil {
 		return "", fmt.Errorf("failed to create identity client: %w", err)
 	}
+	return resolveCompartmentNameToID(identityClient, tenancyOCID, input)
+}
 
-	request := identity.ListCompartmentsRequest{
-		CompartmentId: &tenancyOCID,
-	}
-	response, err := identityClient.ListCompartments(context.Background(), request)
+// resolveCompartmentNameToID resolves a compartment name or path against the (possibly cached)
+// compartment tree of a tenancy; a miss on cached data refreshes it once.
+func resolveCompartmentNameToID(client compartmentLister, tenancyOCID, input string) (string, error) {
+	compartments, fromCache, err := cachedSubtreeCompartments(client, tenancyOCID, false)
 	if err != nil {
 		return "", err
 	}
+	compartmentID, err := findCompartment(compartments, tenancyOCID, input)
+	if errors.Is(err, errCompartmentNotFound) && fromCache {
+		compartments, _, err = cachedSubtreeCompartments(client, tenancyOCID, true)
+		if err != nil {
+			return "", err
+		}
+		compartmentID, err = findCompartment(compartments, tenancyOCID, input)
+	}
+	return compartmentID, err
+}
+
+// findCompartment looks up a compartment name or path in a flat subtree listing of the tenancy.
+func findCompartment(compartments []identity.Compartment, tenancyOCID, input string) (string, error) {
+	// Paths such as "root/dev/team-a" are resolved segment by segment from the tenancy root
+	if input == "root" || strings.Contains(input, "/") {
+		return resolveCompartmentPath(compartments, tenancyOCID, input)
+	}
+
+	// A bare name may refer to a compartment at any depth of the tree
+	paths := compartmentPaths(compartments, tenancyOCID)
 
-	for _, compartment := range response.Items {
-		if *compartment.Name == input {
-			return *compartment.Id, nil
+	var matches []string
+	for _, compartment := range compartments {
+		if compartment.LifecycleState == identity.CompartmentLifecycleStateActive && stringValue(compartment.Name) == input {
+			matches = append(matches, *compartment.Id)
 		}
 	}
 
-	return "", fmt.Errorf("compartment with name '%s' not found", input)
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("compartment with name '%s' not found: %w", input, errCompartmentNotFound)
+	case 1:
+		return matches[0], nil
+	default:
+		descriptions := make([]string, len(matches))
+		for i, id := range matches {
+			descriptions[i] = fmt.Sprintf("%s (%s)", id, paths[id])
+		}
+		return "", fmt.Errorf("multiple compartments named '%s': %s; use the compartment OCID or path instead", input, strings.Join(descriptions, ", "))
+	}
 }
 
-func listCompartmentsRecursive(client identity.IdentityClient, request *identity.ListCompartmentsRequest, depth int) error {
-	var err error
-	response, err := client.ListCompartments(context.Background(), *request)
-	if err != nil {
-		return err
+// resolveCompartmentPath resolves a slash-delimited path such as "team/prod" by walking the
+// compartment tree one level at a time starting from the tenancy root. A leading "root"
+// segment refers to the tenancy itself.
+func resolveCompartmentPath(compartments []identity.Compartment, tenancyOCID, path string) (string, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) > 0 && segments[0] == "root" {
+		segments = segments[1:]
 	}
 
-	for _, compartment := range response.Items {
-		indent := strings.Repeat("  ", depth)
-		fmt.Printf("%sCompartment ID: %s, Name: %s, Description: %s\n", indent, *compartment.Id, *compartment.Name, *compartment.Description)
+	currentID := tenancyOCID
+	for i, segment := range segments {
+		if segment == "" {
+			return "", fmt.Errorf("invalid compartment path '%s': empty segment", path)
+		}
 
-		// Recurse into sub-compartments if any exist
-		if compartment.Id != nil {
-			subRequest := identity.ListCompartmentsRequest{
-				CompartmentId: compartment.Id,
+		found := false
+		for _, compartment := range compartments {
+			if compartment.LifecycleState == identity.CompartmentLifecycleStateActive &&
+				stringValue(compartment.CompartmentId) == currentID && stringValue(compartment.Name) == segment {
+				currentID = *compartment.Id
+				found = true
+				break
 			}
-			err = listCompartmentsRecursive(client, &subRequest, depth+1)
-			if err != nil {
-				return err
+		}
+		if !found {
+			return "", fmt.Errorf("compartment path '%s': segment '%s' not found under '%s': %w", path, segment, strings.Join(append([]string{"root"}, segments[:i]...), "/"), errCompartmentNotFound)
+		}
+	}
+
+	return currentID, nil
+}
+
+// listInstancesInCompartments lists the instances of every compartment in compartmentIDs, scanning
+// at most concurrency compartments in parallel. Each compartment is fetched with a copy of request
+// and the given item and page caps. Results are grouped by compartment, ordered by compartment
+// name (then ID), and keep the request's sort order within a compartment, so the output does not
+// depend on which scan finishes first. maxItems caps the combined result.
+func listInstancesInCompartments(client computeAPI, request core.ListInstancesRequest, compartmentIDs []string, compartmentNames map[string]string, maxItems, maxPages, concurrency int) ([]core.Instance, bool, error) {
+	ordered := append([]string(nil), compartmentIDs...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if compartmentNames[ordered[i]] != compartmentNames[ordered[j]] {
+			return compartmentNames[ordered[i]] < compartmentNames[ordered[j]]
+		}
+		return ordered[i] < ordered[j]
+	})
+
+	results := make([][]core.Instance, len(ordered))
+	more := make([]bool, len(ordered))
+	err := runConcurrently(len(ordered), concurrency, func(i int) error {
+		compartmentRequest := request
+		compartmentRequest.CompartmentId = common.String(ordered[i])
+		items, truncated, err := collectPagesUpTo(maxItems, maxPages, func(page *string) ([]core.Instance, *string, error) {
+			compartmentRequest.Page = page
+			response, err := listInstances(client, compartmentRequest)
+			return response.Items, response.OpcNextPage, err
+		})
+		if err != nil {
+			return fmt.Errorf("listing instances in compartment %s: %w", ordered[i], err)
+		}
+		results[i], more[i] = items, truncated
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	var instances []core.Instance
+	truncated := false
+	for i := range ordered {
+		instances = append(instances, results[i]...)
+		truncated = truncated || more[i]
+	}
+	if maxItems > 0 && len(instances) > maxItems {
+		instances = instances[:maxItems]
+		truncated = true
+	}
+	return instances, truncated, nil
+}
+
+// isSettledInstanceState reports whether an instance is at rest rather than transitioning.
+func isSettledInstanceState(state core.InstanceLifecycleStateEnum) bool {
+	return state == core.InstanceLifecycleStateRunning ||
+		state == core.InstanceLifecycleStateStopped ||
+		state == core.InstanceLifecycleStateTerminated
+}
+
+// followInstance re-renders an instance's details every interval until it reaches a settled
+// state or the user interrupts with Ctrl-C.
+func followInstance(client core.ComputeClient, networkClient core.VirtualNetworkClient, instance core.Instance, interval time.Duration) error {
+	start := time.Now()
+	for !isSettledInstanceState(instance.LifecycleState) {
+		select {
+		case <-rootContext.Done():
+			fmt.Println("\nStopped following instance.")
+			return nil
+		case <-time.After(interval):
+		}
+
+		response, err := getInstance(client, core.GetInstanceRequest{InstanceId: instance.Id})
+		if err != nil {
+			return fmt.Errorf("refreshing instance failed: %w", err)
+		}
+		instance = response.Instance
+		vnics, err := resolveInstanceVnics(client, networkClient, *instance.CompartmentId, *instance.Id)
+		if err != nil {
+			return fmt.Errorf("resolving instance IPs failed: %w", err)
+		}
+		fmt.Printf("\n--- Elapsed: %s ---\n", time.Since(start).Round(time.Second))
+		displayInstanceDetails(&instance, vnics)
+	}
+	fmt.Printf("Instance reached %s after %s.\n", instance.LifecycleState, time.Since(start).Round(time.Second))
+	return nil
+}
+
+// errProvisioningFailed marks a launch that OCI reported as failed: its work request ended FAILED
+// or CANCELED, or the instance went to TERMINATING/TERMINATED. Timeouts, API errors and
+// interruptions do not carry it, as the instance may still come up.
+var errProvisioningFailed = errors.New("instance provisioning failed")
+
+// waitForLaunch follows a launch's work request, printing its progress, and then waits for the
+// instance to be RUNNING, all within timeout. Errors for failed provisioning wrap
+// errProvisioningFailed.
+func waitForLaunch(configProvider common.ConfigurationProvider, computeClient core.ComputeClient, response core.LaunchInstanceResponse, timeout time.Duration) (core.Instance, error) {
+	deadline := time.Now().Add(timeout)
+	if response.OpcWorkRequestId != nil {
+		workRequestClient, err := workrequests.NewWorkRequestClientWithConfigurationProvider(configProvider)
+		if err != nil {
+			return core.Instance{}, fmt.Errorf("creating work request client: %w", err)
+		}
+		if err := waitForWorkRequest(workRequestClient, *response.OpcWorkRequestId, timeout); err != nil {
+			var failed *workRequestFailedError
+			if errors.As(err, &failed) {
+				return core.Instance{}, fmt.Errorf("%w: %w", errProvisioningFailed, err)
 			}
+			return core.Instance{}, err
 		}
 	}
+	instance, err := waitForInstanceState(computeClient, *response.Instance.Id, core.InstanceLifecycleStateRunning, time.Until(deadline))
+	if err != nil && (instance.LifecycleState == core.InstanceLifecycleStateTerminating || instance.LifecycleState == core.InstanceLifecycleStateTerminated) {
+		return instance, fmt.Errorf("%w: %w", errProvisioningFailed, err)
+	}
+	return instance, err
+}
 
-	// Handle pagination if needed (e.g., if there's a next page token)
-	if response.OpcNextPage != nil {
-		nextRequest := *request
-		nextRequest.Page = response.OpcNextPage
-		return listCompartmentsRecursive(client, &nextRequest, depth)
+// waitForInstanceState polls an instance until it reaches target, enters a state from which
+// target can no longer be reached, or timeout elapses. The last fetched instance is always returned.
+func waitForInstanceState(client core.ComputeClient, instanceID string, target core.InstanceLifecycleStateEnum, timeout time.Duration) (core.Instance, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		response, err := getInstance(client, core.GetInstanceRequest{InstanceId: &instanceID})
+		if err != nil {
+			return core.Instance{}, fmt.Errorf("failed to get instance: %w", err)
+		}
+		instance := response.Instance
+		if instance.LifecycleState == target {
+			return instance, nil
+		}
+		if target != core.InstanceLifecycleStateTerminated &&
+			(instance.LifecycleState == core.InstanceLifecycleStateTerminating || instance.LifecycleState == core.InstanceLifecycleStateTerminated) {
+			return instance, fmt.Errorf("instance entered terminal state %s", instance.LifecycleState)
+		}
+		if time.Now().After(deadline) {
+			return instance, fmt.Errorf("timed out after %s waiting for state %s (last state: %s)", timeout, target, instance.LifecycleState)
+		}
+		pause(5 * time.Second)
 	}
+}
 
+// teardownInstance terminates a failed instance, logging each step. Its boot volume is deleted
+// with it unless preserveBootVolume is set, as for a volume the user supplied with
+// --boot-volume-id.
+func teardownInstance(client computeAPI, instanceID string, preserveBootVolume bool) error {
+	if preserveBootVolume {
+		fmt.Printf("Teardown: terminating instance %s and preserving its boot volume...\n", instanceID)
+	} else {
+		fmt.Printf("Teardown: terminating instance %s and deleting its boot volume...\n", instanceID)
+	}
+	_, err := apiCall(client.TerminateInstance, core.TerminateInstanceRequest{
+		InstanceId:         &instanceID,
+		PreserveBootVolume: common.Bool(preserveBootVolume),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to terminate instance %s: %w", instanceID, err)
+	}
+	if preserveBootVolume {
+		fmt.Printf("Teardown: termination of instance %s initiated (boot volume will be preserved).\n", instanceID)
+	} else {
+		fmt.Printf("Teardown: termination of instance %s initiated (boot volume will be deleted with it).\n", instanceID)
+	}
 	return nil
 }
 
-func displayInstanceDetails(instance *core.Instance) {
+// instanceGroupKey returns the value of an instance's --group-by dimension. Compartments are
+// keyed by their path in paths, or by OCID when the path is not known.
+func instanceGroupKey(instance core.Instance, groupBy string, paths map[string]string) (string, error) {
+	switch groupBy {
+	case "compartment":
+		if path, ok := paths[stringValue(instance.CompartmentId)]; ok {
+			return path, nil
+		}
+		return stringValue(instance.CompartmentId), nil
+	case "shape":
+		return stringValue(instance.Shape), nil
+	case "state":
+		return string(instance.LifecycleState), nil
+	case "ad":
+		return stringValue(instance.AvailabilityDomain), nil
+	case "fault-domain":
+		return stringValue(instance.FaultDomain), nil
+	default:
+		return "", fmt.Errorf("invalid --group-by '%s' (must be compartment, shape, state, ad or fault-domain)", groupBy)
+	}
+}
+
+// groupInstances partitions instances by a --group-by dimension, returning the sorted group
+// keys and, per key, the indexes of its instances in their original order.
+func groupInstances(instances []core.Instance, groupBy string, paths map[string]string) ([]string, map[string][]int) {
+	groups := map[string][]int{}
+	var keys []string
+	for i, instance := range instances {
+		key, _ := instanceGroupKey(instance, groupBy, paths)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+	sort.Strings(keys)
+	return keys, groups
+}
+
+// instanceJSON is the serializable projection of an instance shared by the JSON outputs.
+type instanceJSON struct {
+	ID                 string `json:"id"`
+	DisplayName        string `json:"displayName"`
+	LifecycleState     string `json:"lifecycleState"`
+	Shape              string `json:"shape"`
+	ImageID            string `json:"imageId,omitempty"`
+	CompartmentID      string `json:"compartmentId"`
+	CompartmentName    string `json:"compartmentName,omitempty"`
+	AvailabilityDomain string `json:"availabilityDomain"`
+	FaultDomain        string `json:"faultDomain,omitempty"`
+	TimeCreated        string `json:"timeCreated,omitempty"`
+}
+
+// instanceWithVnicsJSON is an instance enriched with its VNIC IP details. PrivateIP and PublicIP
+// are those of the primary VNIC, for scripts that only need the instance's address.
+type instanceWithVnicsJSON struct {
+	instanceJSON
+	PrivateIP string     `json:"privateIp,omitempty"`
+	PublicIP  string     `json:"publicIp,omitempty"`
+	Vnics     []vnicJSON `json:"vnics"`
+}
+
+// newInstanceWithVnicsJSON combines an instance with its VNICs. Without a VNIC marked primary,
+// the first one provides the top-level IPs.
+func newInstanceWithVnicsJSON(details instanceJSON, vnics []vnicJSON) instanceWithVnicsJSON {
+	result := instanceWithVnicsJSON{instanceJSON: details, Vnics: vnics}
+	if len(vnics) == 0 {
+		return result
+	}
+	primary := vnics[0]
+	for _, vnic := range vnics {
+		if vnic.Primary {
+			primary = vnic
+			break
+		}
+	}
+	result.PrivateIP, result.PublicIP = primary.PrivateIP, primary.PublicIP
+	return result
+}
+
+func newInstanceJSON(instance *core.Instance) instanceJSON {
+	result := instanceJSON{
+		ID:                 stringValue(instance.Id),
+		DisplayName:        stringValue(instance.DisplayName),
+		LifecycleState:     string(instance.LifecycleState),
+		Shape:              stringValue(instance.Shape),
+		ImageID:            stringValue(instance.ImageId),
+		CompartmentID:      stringValue(instance.CompartmentId),
+		AvailabilityDomain: stringValue(instance.AvailabilityDomain),
+		FaultDomain:        stringValue(instance.FaultDomain),
+	}
+	if instance.TimeCreated != nil {
+		result.TimeCreated = instance.TimeCreated.Format(time.RFC3339)
+	}
+	return result
+}
+
+// instanceTable renders instances as an aligned text table.
+type instanceTable struct {
+	instances        []core.Instance
+	compartmentNames map[string]string
+	showCompartment  bool
+	showIPs          bool
+	vnics            [][]vnicJSON
+	fields           []instanceField // replaces the default columns when set
+}
+
+// print writes a table of the instances at the given indices. OCPUs and memory are only
+// shown for Flex shapes.
+func (t instanceTable) print(cmd *cobra.Command, indices []int) {
+	if len(t.fields) > 0 {
+		records := make([]instanceRecord, len(indices))
+		for row, i := range indices {
+			records[row] = instanceRecord{instance: t.instances[i], compartmentName: t.compartmentNames[stringValue(t.instances[i].CompartmentId)]}
+			if t.showIPs {
+				records[row].vnics = t.vnics[i]
+			}
+		}
+		printInstanceFieldsTable(cmd, t.fields, records)
+		return
+	}
+	var columns []tableColumn
+	if t.showCompartment {
+		columns = append(columns, tableColumn{header: "COMPARTMENT"})
+	}
+	columns = append(columns,
+		tableColumn{header: "ID", ocid: true},
+		tableColumn{header: "DISPLAY NAME"},
+		tableColumn{header: "STATE"},
+		tableColumn{header: "CREATED"},
+		tableColumn{header: "SHAPE"},
+		tableColumn{header: "OCPUS", numeric: true},
+		tableColumn{header: "MEMORY (GB)", numeric: true},
+	)
+	if t.showIPs {
+		columns = append(columns, tableColumn{header: "PRIVATE IPS"}, tableColumn{header: "PUBLIC IPS"})
+	}
+	table := newTextTable(cmd, columns...)
+
+	for _, i := range indices {
+		instance := t.instances[i]
+		created, ocpus, memory := "-", "-", "-"
+		if instance.TimeCreated != nil {
+			created = instance.TimeCreated.Format("2006-01-02 15:04")
+		}
+		if strings.HasSuffix(stringValue(instance.Shape), ".Flex") && instance.ShapeConfig != nil {
+			if instance.ShapeConfig.Ocpus != nil {
+				ocpus = fmt.Sprintf("%g", *instance.ShapeConfig.Ocpus)
+			}
+			if instance.ShapeConfig.MemoryInGBs != nil {
+				memory = fmt.Sprintf("%g", *instance.ShapeConfig.MemoryInGBs)
+			}
+		}
+		var row []string
+		if t.showCompartment {
+			row = append(row, t.compartmentNames[stringValue(instance.CompartmentId)])
+		}
+		row = append(row, stringValue(instance.Id), stringValue(instance.DisplayName), string(instance.LifecycleState), created, stringValue(instance.Shape), ocpus, memory)
+		if t.showIPs {
+			var privateIPs, publicIPs []string
+			for _, vnic := range t.vnics[i] {
+				if vnic.PrivateIP != "" {
+					privateIPs = append(privateIPs, vnic.PrivateIP)
+				}
+				if vnic.PublicIP != "" {
+					publicIPs = append(publicIPs, vnic.PublicIP)
+				}
+			}
+			row = append(row, joinOrDash(privateIPs), joinOrDash(publicIPs))
+		}
+		table.addRow(row...)
+	}
+	table.print()
+}
+
+// joinOrDash joins values with commas, or returns "-" when there are none.
+func joinOrDash(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ",")
+}
+
+// displayInstanceDetails prints an instance and the IPs of its VNICs.
+func displayInstanceDetails(instance *core.Instance, vnics []vnicJSON) {
+	details := newInstanceJSON(instance)
 	fmt.Println("Instance Details:")
-	fmt.Printf("  ID: %s\n", *instance.Id)
-	fmt.Printf("  Display Name: %s\n", *instance.DisplayName)
-	fmt.Printf("  State: %s\n", instance.LifecycleState)
-	fmt.Printf("  Shape: %s\n", *instance.Shape)
-	fmt.Printf("  Image ID: %s\n", *instance.ImageId)
-	fmt.Printf("  Compartment ID: %s\n", *instance.CompartmentId)
-	fmt.Printf("  Availability Domain: %s\n", *instance.AvailabilityDomain)
-	fmt.Printf("  Fault Domain: %s\n", *instance.FaultDomain)
+	fmt.Printf("  ID: %s\n", details.ID)
+	fmt.Printf("  Display Name: %s\n", details.DisplayName)
+	fmt.Printf("  State: %s\n", details.LifecycleState)
+	fmt.Printf("  Shape: %s\n", details.Shape)
+	fmt.Printf("  Image ID: %s\n", details.ImageID)
+	fmt.Printf("  Compartment ID: %s\n", details.CompartmentID)
+	fmt.Printf("  Availability Domain: %s\n", details.AvailabilityDomain)
+	fmt.Printf("  Fault Domain: %s\n", details.FaultDomain)
+	if len(vnics) == 0 {
+		fmt.Println("  VNICs: (none attached)")
+		return
+	}
+	fmt.Println("  VNICs:")
+	for _, vnic := range vnics {
+		publicIP := vnic.PublicIP
+		if publicIP == "" {
+			publicIP = "(none)"
+		}
+		hostname := vnic.Hostname
+		if hostname == "" {
+			hostname = "(none)"
+		}
+		fmt.Printf("    - VNIC ID: %s (primary: %t)\n", vnic.VnicID, vnic.Primary)
+		fmt.Printf("      Private IP: %s\n", vnic.PrivateIP)
+		fmt.Printf("      Public IP: %s\n", publicIP)
+		fmt.Printf("      Hostname: %s\n", hostname)
+	}
 }
 
 // resolveImageNameToID finds the OCID for a given image display name.
 // It needs the tenancyOCID for fallback searches for platform images.
-func resolveImageNameToID(imageName, compartmentID, tenancyOCID string, client core.ComputeClient) (string, error) {
+func resolveImageNameToID(imageName, compartmentID, tenancyOCID string, strict bool, client computeAPI) (string, error) {
 	request := core.ListImagesRequest{
 		CompartmentId: &compartmentID,
 		DisplayName:   &imageName,
 		// Add other filters if needed, e.g., OperatingSystem
 	}
-	response, err := client.ListImages(context.Background(), request)
+	response, err := listImages(client, request)
 	if err != nil {
 		return "", fmt.Errorf("failed to list images: %w", err)
 	}
@@ -667,7 +1807,7 @@ func resolveImageNameToID(imageName, compartmentID, tenancyOCID string, client c
 		// Try searching using the tenancy OCID (common practice for platform images)
 		fmt.Printf("Image '%s' not found in compartment '%s', checking platform images...\n", imageName, compartmentID)
 		request.CompartmentId = &tenancyOCID // Use Tenancy OCID for fallback
-		responseOracle, errOracle := client.ListImages(context.Background(), request)
+		responseOracle, errOracle := listImages(client, request)
 		if errOracle != nil {
 			// Provide more context in the error
 			return "", fmt.Errorf("failed to list platform images (using tenancy %s): %w", tenancyOCID, errOracle)
@@ -676,38 +1816,318 @@ func resolveImageNameToID(imageName, compartmentID, tenancyOCID string, client c
 			return "", fmt.Errorf("no image found with name '%s' in compartment '%s' or platform images (searched tenancy %s)", imageName, compartmentID, tenancyOCID)
 		}
 		if len(responseOracle.Items) > 1 {
+			if strict {
+				return "", ambiguousImageError(imageName, responseOracle.Items)
+			}
 			fmt.Printf("Warning: Multiple platform images found with name '%s'. Using the first one.\n", imageName)
 		}
 		return *responseOracle.Items[0].Id, nil
 	}
 
 	if len(response.Items) > 1 {
+		if strict {
+			return "", ambiguousImageError(imageName, response.Items)
+		}
 		fmt.Printf("Warning: Multiple images found with name '%s' in compartment '%s'. Using the first one.\n", imageName, compartmentID)
 	}
 
 	return *response.Items[0].Id, nil
 }
 
+// imageMinimumBootVolumeSizeInGBs returns the smallest boot volume, in whole GB, that holds an image.
+func imageMinimumBootVolumeSizeInGBs(client computeAPI, imageID string) (int64, error) {
+	response, err := apiCall(client.GetImage, core.GetImageRequest{ImageId: &imageID})
+	if err != nil {
+		return 0, fmt.Errorf("getting image '%s' to check the boot volume size: %w", imageID, err)
+	}
+	if response.Image.SizeInMBs == nil {
+		return 0, nil
+	}
+	return (*response.Image.SizeInMBs + 1023) / 1024, nil
+}
+
+// ambiguousImageError lists every image matching a display name, for --strict-image-match.
+func ambiguousImageError(imageName string, images []core.Image) error {
+	lines := make([]string, len(images))
+	for i, image := range images {
+		created := "-"
+		if image.TimeCreated != nil {
+			created = image.TimeCreated.Format(time.RFC3339)
+		}
+		lines[i] = fmt.Sprintf("  %s (created %s)", stringValue(image.Id), created)
+	}
+	return fmt.Errorf("%d images found with name '%s':\n%s", len(images), imageName, strings.Join(lines, "\n"))
+}
+
+// resolveNewestImageByOS returns the most recently created image with the given operating system
+// (and version, if not empty) that is compatible with shapeName, so launches keep working as
+// Oracle publishes rebuilt images under new display names.
+func resolveNewestImageByOS(operatingSystem, version, shapeName, compartmentID string, client computeAPI) (string, error) {
+	request := core.ListImagesRequest{
+		CompartmentId:   &compartmentID,
+		OperatingSystem: &operatingSystem,
+		Shape:           &shapeName,
+		LifecycleState:  core.ImageLifecycleStateAvailable,
+		SortBy:          core.ListImagesSortByTimecreated,
+		SortOrder:       core.ListImagesSortOrderDesc,
+	}
+	if version != "" {
+		request.OperatingSystemVersion = &version
+	}
+	images, _, err := collectPages(0, func(page *string) ([]core.Image, *string, error) {
+		request.Page = page
+		response, err := listImages(client, request)
+		return response.Items, response.OpcNextPage, err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list images: %w", err)
+	}
+
+	var newest *core.Image
+	for i, image := range images {
+		if image.TimeCreated == nil {
+			continue
+		}
+		if newest == nil || image.TimeCreated.After(newest.TimeCreated.Time) {
+			newest = &images[i]
+		}
+	}
+	if newest == nil {
+		return "", fmt.Errorf("no image found for operating system '%s' version '%s' compatible with shape '%s'", operatingSystem, version, shapeName)
+	}
+	fmt.Printf("Selected image '%s' (created %s)\n", stringValue(newest.DisplayName), newest.TimeCreated.Format(time.RFC3339))
+	return *newest.Id, nil
+}
+
+// resolveSubnetNameToID finds the OCID of a subnet by display name within a compartment, optionally
+// restricted to one VCN. A name shared by subnets of several VCNs is an error unless vcnID is given.
+func resolveSubnetNameToID(subnetName, compartmentID, vcnID string, client core.VirtualNetworkClient) (string, error) {
+	request := core.ListSubnetsRequest{
+		CompartmentId: &compartmentID,
+		DisplayName:   &subnetName,
+	}
+	if vcnID != "" {
+		request.VcnId = &vcnID
+	}
+	subnets, _, err := collectPages(0, func(page *string) ([]core.Subnet, *string, error) {
+		request.Page = page
+		response, err := apiCall(client.ListSubnets, request)
+		return response.Items, response.OpcNextPage, err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list subnets: %w", err)
+	}
+
+	if len(subnets) == 0 {
+		return "", fmt.Errorf("no subnet found with name '%s' in compartment '%s'", subnetName, compartmentID)
+	}
+	if len(subnets) > 1 {
+		var matches []string
+		for _, subnet := range subnets {
+			matches = append(matches, fmt.Sprintf("%s (VCN %s)", *subnet.Id, stringValue(subnet.VcnId)))
+		}
+		return "", fmt.Errorf("multiple subnets named '%s' (%s); use --vcn-id to choose one", subnetName, strings.Join(matches, ", "))
+	}
+	return *subnets[0].Id, nil
+}
+
+// resolveInstanceNameToID finds the OCID of the non-terminated instance with the given display
+// name in a compartment. Multiple matches are an error since names are not unique.
+func resolveInstanceNameToID(instanceName, compartmentID string, client core.ComputeClient) (string, error) {
+	request := core.ListInstancesRequest{
+		CompartmentId: &compartmentID,
+		DisplayName:   &instanceName,
+	}
+	var matches []string
+	for {
+		response, err := listInstances(client, request)
+		if err != nil {
+			return "", fmt.Errorf("failed to list instances: %w", err)
+		}
+		for _, instance := range response.Items {
+			if instance.LifecycleState != core.InstanceLifecycleStateTerminated {
+				matches = append(matches, *instance.Id)
+			}
+		}
+		if response.OpcNextPage == nil {
+			break
+		}
+		request.Page = response.OpcNextPage
+	}
+
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no instance found with name '%s' in compartment '%s'", instanceName, compartmentID)
+	}
+	if len(matches) > 1 {
+		return "", fmt.Errorf("multiple instances named '%s' in compartment '%s' (%s); use the instance OCID instead", instanceName, compartmentID, strings.Join(matches, ", "))
+	}
+	return matches[0], nil
+}
+
+// applyLaunchOptionOverrides sets any explicitly requested launch options on top of base.
+// It returns base unchanged (possibly nil) when no overrides are given.
+func applyLaunchOptionOverrides(base *core.LaunchOptions, firmware, networkType, bootVolumeType, remoteDataVolumeType string) (*core.LaunchOptions, error) {
+	if firmware == "" && networkType == "" && bootVolumeType == "" && remoteDataVolumeType == "" {
+		return base, nil
+	}
+
+	options := core.LaunchOptions{}
+	if base != nil {
+		options = *base
+	}
+
+	if firmware != "" {
+		value, ok := core.GetMappingLaunchOptionsFirmwareEnum(firmware)
+		if !ok {
+			return nil, fmt.Errorf("invalid --firmware '%s' (valid: %s)", firmware, strings.Join(core.GetLaunchOptionsFirmwareEnumStringValues(), ", "))
+		}
+		options.Firmware = value
+	}
+	if networkType != "" {
+		value, ok := core.GetMappingLaunchOptionsNetworkTypeEnum(networkType)
+		if !ok {
+			return nil, fmt.Errorf("invalid --network-type '%s' (valid: %s)", networkType, strings.Join(core.GetLaunchOptionsNetworkTypeEnumStringValues(), ", "))
+		}
+		options.NetworkType = value
+	}
+	if bootVolumeType != "" {
+		value, ok := core.GetMappingLaunchOptionsBootVolumeTypeEnum(bootVolumeType)
+		if !ok {
+			return nil, fmt.Errorf("invalid --boot-volume-type '%s' (valid: %s)", bootVolumeType, strings.Join(core.GetLaunchOptionsBootVolumeTypeEnumStringValues(), ", "))
+		}
+		options.BootVolumeType = value
+	}
+	if remoteDataVolumeType != "" {
+		value, ok := core.GetMappingLaunchOptionsRemoteDataVolumeTypeEnum(remoteDataVolumeType)
+		if !ok {
+			return nil, fmt.Errorf("invalid --remote-data-volume-type '%s' (valid: %s)", remoteDataVolumeType, strings.Join(core.GetLaunchOptionsRemoteDataVolumeTypeEnumStringValues(), ", "))
+		}
+		options.RemoteDataVolumeType = value
+	}
+
+	return &options, nil
+}
+
+// describeLaunchOptions renders the set launch options as a single line.
+func describeLaunchOptions(options *core.LaunchOptions) string {
+	var parts []string
+	if options.Firmware != "" {
+		parts = append(parts, fmt.Sprintf("firmware=%s", options.Firmware))
+	}
+	if options.NetworkType != "" {
+		parts = append(parts, fmt.Sprintf("networkType=%s", options.NetworkType))
+	}
+	if options.BootVolumeType != "" {
+		parts = append(parts, fmt.Sprintf("bootVolumeType=%s", options.BootVolumeType))
+	}
+	if options.RemoteDataVolumeType != "" {
+		parts = append(parts, fmt.Sprintf("remoteDataVolumeType=%s", options.RemoteDataVolumeType))
+	}
+	if options.IsPvEncryptionInTransitEnabled != nil {
+		parts = append(parts, fmt.Sprintf("pvEncryptionInTransit=%t", *options.IsPvEncryptionInTransitEnabled))
+	}
+	if options.IsConsistentVolumeNamingEnabled != nil {
+		parts = append(parts, fmt.Sprintf("consistentVolumeNaming=%t", *options.IsConsistentVolumeNamingEnabled))
+	}
+	if len(parts) == 0 {
+		return "(none)"
+	}
+	return strings.Join(parts, ", ")
+}
+
 // resolveShapeNameToID finds the OCID for a given shape name.
 // Note: Shape OCIDs are usually not required, the name often suffices, but this provides flexibility.
-func resolveShapeNameToID(shapeName string, compartmentID string, imageID string, client core.ComputeClient) (string, error) {
+func resolveShapeNameToID(shapeName string, compartmentID string, imageID string, ocpus, memoryInGBs float32, client computeAPI) (string, error) {
 	request := core.ListShapesRequest{
 		CompartmentId: &compartmentID,
-		ImageId:       &imageID, // Shapes depend on the image
 	}
-	response, err := client.ListShapes(context.Background(), request)
+	if imageID != "" {
+		request.ImageId = &imageID // Shapes depend on the image
+	}
+	shapes, _, err := collectPages(0, func(page *string) ([]core.Shape, *string, error) {
+		request.Page = page
+		response, err := listShapes(client, request)
+		return response.Items, response.OpcNextPage, err
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to list shapes: %w", err)
 	}
 
-	for _, shape := range response.Items {
-		if shape.Shape != nil && *shape.Shape == shapeName {
-			// The SDK often uses the shape *name* directly, but we found it.
-			// If the API truly needed the shape OCID (uncommon), we'd return it here.
-			// For now, we just confirm it exists and return the name as the API expects.
+	var compatible []string
+	for _, shape := range shapes {
+		if shape.Shape == nil {
+			continue
+		}
+		if *shape.Shape == shapeName {
+			// Flex shapes have no default size, so the launch would be rejected without one
+			if shape.OcpuOptions != nil && ocpus == 0 {
+				return "", fmt.Errorf("'%s' is a Flex shape; specify --ocpus (between %.0f and %.0f) and optionally --memory-in-gbs", shapeName, float32Value(shape.OcpuOptions.Min), float32Value(shape.OcpuOptions.Max))
+			}
+			if err := validateShapeConfig(shape, ocpus, memoryInGBs); err != nil {
+				return "", err
+			}
+			// The SDK uses the shape *name* directly; we just confirm it exists.
 			return shapeName, nil
 		}
+		compatible = append(compatible, *shape.Shape)
+	}
+
+	sort.Strings(compatible)
+	if len(compatible) == 0 {
+		return "", fmt.Errorf("no shape found with name '%s' compatible with image '%s' in compartment '%s' (the image is not compatible with any shape)", shapeName, imageID, compartmentID)
+	}
+	return "", fmt.Errorf("no shape found with name '%s' compatible with image '%s' in compartment '%s'; compatible shapes: %s", shapeName, imageID, compartmentID, strings.Join(compatible, ", "))
+}
+
+// filterShapes keeps the Flex shapes (when flexOnly is set) whose name contains the given text.
+func filterShapes(shapes []core.Shape, flexOnly bool, contains string) []core.Shape {
+	var matched []core.Shape
+	for _, shape := range shapes {
+		if flexOnly && shape.OcpuOptions == nil {
+			continue
+		}
+		if contains != "" && !strings.Contains(strings.ToLower(stringValue(shape.Shape)), strings.ToLower(contains)) {
+			continue
+		}
+		matched = append(matched, shape)
 	}
+	return matched
+}
 
-	return "", fmt.Errorf("no shape found with name '%s' compatible with image '%s' in compartment '%s'", shapeName, imageID, compartmentID)
+// validateShapeConfig checks requested Flex OCPUs and memory against the shape's ranges, so an
+// invalid size fails locally instead of in LaunchInstance. A memory-per-OCPU ratio outside the
+// shape's supported range only produces a warning.
+func validateShapeConfig(shape core.Shape, ocpus, memoryInGBs float32) error {
+	name := stringValue(shape.Shape)
+	if options := shape.OcpuOptions; options != nil && ocpus != 0 {
+		if (options.Min != nil && ocpus < *options.Min) || (options.Max != nil && ocpus > *options.Max) {
+			return fmt.Errorf("--ocpus %g is outside the range of shape '%s' (%g to %g)", ocpus, name, float32Value(options.Min), float32Value(options.Max))
+		}
+	}
+	options := shape.MemoryOptions
+	if options == nil || memoryInGBs == 0 {
+		return nil
+	}
+	if (options.MinInGBs != nil && memoryInGBs < *options.MinInGBs) || (options.MaxInGBs != nil && memoryInGBs > *options.MaxInGBs) {
+		return fmt.Errorf("--memory-in-gbs %g is outside the range of shape '%s' (%g to %g GB)", memoryInGBs, name, float32Value(options.MinInGBs), float32Value(options.MaxInGBs))
+	}
+	if ocpus != 0 {
+		perOcpu := memoryInGBs / ocpus
+		if (options.MinPerOcpuInGBs != nil && perOcpu < *options.MinPerOcpuInGBs) || (options.MaxPerOcpuInGBs != nil && perOcpu > *options.MaxPerOcpuInGBs) {
+			fmt.Printf("Warning: %g GB per OCPU is outside the ratio supported by shape '%s' (%g to %g GB per OCPU); the launch may be rejected.\n", perOcpu, name, float32Value(options.MinPerOcpuInGBs), float32Value(options.MaxPerOcpuInGBs))
+		}
+	}
+	return nil
+}
+
+// maxMetadataSize is the largest combined size of instance metadata OCI accepts.
+const maxMetadataSize = 32000
+
+// metadataSize returns the combined size in bytes of the keys and values of instance metadata.
+func metadataSize(metadata map[string]string) int {
+	size := 0
+	for key, value := range metadata {
+		size += len(key) + len(value)
+	}
+	return size
 }
@@ -0,0 +1,503 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+)
+
+// fakeCompute is a computeAPI serving fixed images, shapes and instances. Methods a test does not need
+// are left to the embedded nil interface and panic if called.
+type fakeCompute struct {
+	computeAPI
+	images        map[string][]core.Image // compartment ID -> images
+	shapePages    [][]core.Shape
+	instancePages map[string][][]core.Instance // compartment ID -> pages of instances
+	err           error
+	imageQueries  []string // compartment ID of every ListImages call
+	terminations  []core.TerminateInstanceRequest
+}
+
+func (f *fakeCompute) ListImages(_ context.Context, request core.ListImagesRequest) (core.ListImagesResponse, error) {
+	f.imageQueries = append(f.imageQueries, stringValue(request.CompartmentId))
+	if f.err != nil {
+		return core.ListImagesResponse{}, f.err
+	}
+	var response core.ListImagesResponse
+	for _, image := range f.images[stringValue(request.CompartmentId)] {
+		if request.DisplayName == nil || stringValue(image.DisplayName) == *request.DisplayName {
+			response.Items = append(response.Items, image)
+		}
+	}
+	return response, nil
+}
+
+func (f *fakeCompute) ListShapes(_ context.Context, request core.ListShapesRequest) (core.ListShapesResponse, error) {
+	if f.err != nil {
+		return core.ListShapesResponse{}, f.err
+	}
+	index := 0
+	if request.Page != nil {
+		fmt.Sscanf(*request.Page, "%d", &index)
+	}
+	var response core.ListShapesResponse
+	if index < len(f.shapePages) {
+		response.Items = f.shapePages[index]
+	}
+	if index+1 < len(f.shapePages) {
+		response.OpcNextPage = common.String(fmt.Sprint(index + 1))
+	}
+	return response, nil
+}
+
+// ListInstances is safe for concurrent use as long as the fake is not modified.
+func (f *fakeCompute) ListInstances(_ context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+	if f.err != nil {
+		return core.ListInstancesResponse{}, f.err
+	}
+	pages := f.instancePages[stringValue(request.CompartmentId)]
+	index := 0
+	if request.Page != nil {
+		fmt.Sscanf(*request.Page, "%d", &index)
+	}
+	var response core.ListInstancesResponse
+	if index < len(pages) {
+		response.Items = pages[index]
+	}
+	if index+1 < len(pages) {
+		response.OpcNextPage = common.String(fmt.Sprint(index + 1))
+	}
+	return response, nil
+}
+
+func (f *fakeCompute) TerminateInstance(_ context.Context, request core.TerminateInstanceRequest) (core.TerminateInstanceResponse, error) {
+	f.terminations = append(f.terminations, request)
+	return core.TerminateInstanceResponse{}, f.err
+}
+
+func testImage(id, name string) core.Image {
+	return core.Image{Id: common.String(id), DisplayName: common.String(name)}
+}
+
+func TestResolveImageNameToID(t *testing.T) {
+	const compartmentID, tenancyOCID = "ocid1.compartment.oc1..dev", "ocid1.tenancy.oc1..root"
+	client := &fakeCompute{images: map[string][]core.Image{
+		compartmentID: {
+			testImage("ocid1.image.oc1..custom", "custom"),
+			testImage("ocid1.image.oc1..dup1", "duplicate"),
+			testImage("ocid1.image.oc1..dup2", "duplicate"),
+		},
+		tenancyOCID: {
+			testImage("ocid1.image.oc1..ubuntu", "Canonical-Ubuntu-24.04"),
+		},
+	}}
+
+	tests := []struct {
+		name      string
+		client    computeAPI
+		imageName string
+		strict    bool
+		want      string
+		wantErr   string
+	}{
+		{name: "compartment image", client: client, imageName: "custom", want: "ocid1.image.oc1..custom"},
+		{name: "platform image fallback", client: client, imageName: "Canonical-Ubuntu-24.04", want: "ocid1.image.oc1..ubuntu"},
+		{name: "not found", client: client, imageName: "missing", wantErr: "no image found with name 'missing'"},
+		{name: "ambiguous uses first", client: client, imageName: "duplicate", want: "ocid1.image.oc1..dup1"},
+		{name: "ambiguous strict", client: client, imageName: "duplicate", strict: true, wantErr: "ocid1.image.oc1..dup2"},
+		{name: "list error", client: &fakeCompute{err: errors.New("boom")}, imageName: "custom", wantErr: "boom"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := resolveImageNameToID(test.imageName, compartmentID, tenancyOCID, test.strict, test.client)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("image ID = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// captureStdout returns what fn prints to stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	writer.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestResolveImageNameToIDFallback(t *testing.T) {
+	const compartmentID, tenancyOCID = "ocid1.compartment.oc1..dev", "ocid1.tenancy.oc1..root"
+	images := map[string][]core.Image{
+		compartmentID: {
+			testImage("ocid1.image.oc1..custom", "custom"),
+			testImage("ocid1.image.oc1..dup1", "duplicate"),
+			testImage("ocid1.image.oc1..dup2", "duplicate"),
+		},
+		tenancyOCID: {
+			testImage("ocid1.image.oc1..ubuntu", "ubuntu"),
+			testImage("ocid1.image.oc1..oracle1", "oracle-linux"),
+			testImage("ocid1.image.oc1..oracle2", "oracle-linux"),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		imageName   string
+		want        string
+		wantErr     string
+		wantQueries []string
+		wantWarning string
+	}{
+		{name: "found in compartment", imageName: "custom", want: "ocid1.image.oc1..custom", wantQueries: []string{compartmentID}},
+		{name: "found in tenancy", imageName: "ubuntu", want: "ocid1.image.oc1..ubuntu", wantQueries: []string{compartmentID, tenancyOCID}},
+		{name: "found nowhere", imageName: "missing", wantErr: "no image found with name 'missing'", wantQueries: []string{compartmentID, tenancyOCID}},
+		{name: "multiple in compartment", imageName: "duplicate", want: "ocid1.image.oc1..dup1", wantQueries: []string{compartmentID}, wantWarning: "Warning: Multiple images found with name 'duplicate'"},
+		{name: "multiple in tenancy", imageName: "oracle-linux", want: "ocid1.image.oc1..oracle1", wantQueries: []string{compartmentID, tenancyOCID}, wantWarning: "Warning: Multiple platform images found with name 'oracle-linux'"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &fakeCompute{images: images}
+			var got string
+			var err error
+			output := captureStdout(t, func() {
+				got, err = resolveImageNameToID(test.imageName, compartmentID, tenancyOCID, false, client)
+			})
+
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, test.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			} else if got != test.want {
+				t.Errorf("image ID = %q, want %q", got, test.want)
+			}
+			if !reflect.DeepEqual(client.imageQueries, test.wantQueries) {
+				t.Errorf("ListImages compartments = %v, want %v", client.imageQueries, test.wantQueries)
+			}
+			if test.wantWarning != "" && !strings.Contains(output, test.wantWarning) {
+				t.Errorf("output %q does not contain warning %q", output, test.wantWarning)
+			}
+			if test.wantWarning == "" && strings.Contains(output, "Warning:") {
+				t.Errorf("unexpected warning in output %q", output)
+			}
+		})
+	}
+}
+
+func TestResolveShapeNameToID(t *testing.T) {
+	flex := core.Shape{
+		Shape:         common.String("VM.Standard.A1.Flex"),
+		OcpuOptions:   &core.ShapeOcpuOptions{Min: common.Float32(1), Max: common.Float32(4)},
+		MemoryOptions: &core.ShapeMemoryOptions{MinInGBs: common.Float32(1), MaxInGBs: common.Float32(24), MinPerOcpuInGBs: common.Float32(1), MaxPerOcpuInGBs: common.Float32(64)},
+	}
+	client := &fakeCompute{shapePages: [][]core.Shape{
+		{{Shape: common.String("VM.Standard2.1")}},
+		{{Shape: common.String("VM.Standard.E2.1.Micro")}, flex},
+	}}
+
+	tests := []struct {
+		name    string
+		client  computeAPI
+		shape   string
+		ocpus   float32
+		memory  float32
+		wantErr string
+	}{
+		{name: "fixed shape", client: client, shape: "VM.Standard2.1"},
+		{name: "shape on later page", client: client, shape: "VM.Standard.E2.1.Micro"},
+		{name: "flex with ocpus", client: client, shape: "VM.Standard.A1.Flex", ocpus: 2, memory: 12},
+		{name: "flex without ocpus", client: client, shape: "VM.Standard.A1.Flex", wantErr: "specify --ocpus"},
+		{name: "ocpus out of range", client: client, shape: "VM.Standard.A1.Flex", ocpus: 8, wantErr: "--ocpus 8 is outside the range"},
+		{name: "memory out of range", client: client, shape: "VM.Standard.A1.Flex", ocpus: 2, memory: 48, wantErr: "--memory-in-gbs 48 is outside the range"},
+		{name: "unknown shape lists alternatives", client: client, shape: "VM.Standard3.Flex", wantErr: "compatible shapes: VM.Standard.A1.Flex, VM.Standard.E2.1.Micro, VM.Standard2.1"},
+		{name: "incompatible image", client: &fakeCompute{}, shape: "VM.Standard2.1", wantErr: "not compatible with any shape"},
+		{name: "list error", client: &fakeCompute{err: errors.New("boom")}, shape: "VM.Standard2.1", wantErr: "boom"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := resolveShapeNameToID(test.shape, "ocid1.compartment.oc1..dev", "ocid1.image.oc1..image", test.ocpus, test.memory, test.client)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != test.shape {
+				t.Errorf("shape = %q, want %q", got, test.shape)
+			}
+		})
+	}
+}
+
+func testCompartment(id, parentID, name string, state identity.CompartmentLifecycleStateEnum) identity.Compartment {
+	return identity.Compartment{Id: common.String(id), CompartmentId: common.String(parentID), Name: common.String(name), LifecycleState: state}
+}
+
+func TestResolveCompartmentID(t *testing.T) {
+	const tenancyOCID = "ocid1.tenancy.oc1..root"
+	active := identity.CompartmentLifecycleStateActive
+	compartments := []identity.Compartment{
+		testCompartment("ocid1.compartment.oc1..dev", tenancyOCID, "dev", active),
+		testCompartment("ocid1.compartment.oc1..prod", tenancyOCID, "prod", active),
+		testCompartment("ocid1.compartment.oc1..devteam", "ocid1.compartment.oc1..dev", "team-a", active),
+		testCompartment("ocid1.compartment.oc1..prodteam", "ocid1.compartment.oc1..prod", "team-a", active),
+		testCompartment("ocid1.compartment.oc1..old", tenancyOCID, "old", identity.CompartmentLifecycleStateDeleted),
+	}
+	client := compartmentListerFunc(func(request identity.ListCompartmentsRequest) (identity.ListCompartmentsResponse, error) {
+		if stringValue(request.CompartmentId) != tenancyOCID || request.CompartmentIdInSubtree == nil || !*request.CompartmentIdInSubtree {
+			return identity.ListCompartmentsResponse{}, fmt.Errorf("unexpected request for %s", stringValue(request.CompartmentId))
+		}
+		return identity.ListCompartmentsResponse{Items: compartments}, nil
+	})
+
+	// Bypass the on-disk cache
+	ttl := compartmentCacheTTL
+	compartmentCacheTTL = 0
+	defer func() { compartmentCacheTTL = ttl }()
+
+	tests := []struct {
+		name     string
+		input    string
+		want     string
+		wantErr  string
+		notFound bool
+	}{
+		{name: "name", input: "dev", want: "ocid1.compartment.oc1..dev"},
+		{name: "root", input: "root", want: tenancyOCID},
+		{name: "path", input: "root/prod/team-a", want: "ocid1.compartment.oc1..prodteam"},
+		{name: "path without root", input: "dev/team-a", want: "ocid1.compartment.oc1..devteam"},
+		{name: "ambiguous name", input: "team-a", wantErr: "ocid1.compartment.oc1..prodteam"},
+		{name: "missing name", input: "nope", notFound: true},
+		{name: "deleted name", input: "old", notFound: true},
+		{name: "missing path segment", input: "root/dev/team-b", notFound: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := resolveCompartmentNameToID(client, tenancyOCID, test.input)
+			switch {
+			case test.notFound:
+				if !errors.Is(err, errCompartmentNotFound) {
+					t.Fatalf("error = %v, want errCompartmentNotFound", err)
+				}
+			case test.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, test.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case got != test.want:
+				t.Errorf("compartment ID = %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	t.Run("OCID is returned as-is", func(t *testing.T) {
+		got, err := resolveCompartmentID("ocid1.compartment.oc1..anything", nil)
+		if err != nil || got != "ocid1.compartment.oc1..anything" {
+			t.Errorf("resolveCompartmentID = %q, %v", got, err)
+		}
+	})
+}
+
+func TestListInstancesInCompartments(t *testing.T) {
+	instance := func(name string) core.Instance {
+		return core.Instance{DisplayName: common.String(name)}
+	}
+	client := &fakeCompute{instancePages: map[string][][]core.Instance{
+		"ocid1.compartment.oc1..a": {{instance("a1"), instance("a2")}, {instance("a3")}},
+		"ocid1.compartment.oc1..b": {{instance("b1")}},
+		"ocid1.compartment.oc1..c": nil,
+		"ocid1.compartment.oc1..d": {{instance("d1")}},
+	}}
+	compartmentIDs := []string{"ocid1.compartment.oc1..a", "ocid1.compartment.oc1..b", "ocid1.compartment.oc1..c", "ocid1.compartment.oc1..d"}
+	names := map[string]string{
+		"ocid1.compartment.oc1..a": "zeta",
+		"ocid1.compartment.oc1..b": "alpha",
+		"ocid1.compartment.oc1..c": "beta",
+		"ocid1.compartment.oc1..d": "alpha",
+	}
+
+	tests := []struct {
+		name          string
+		maxItems      int
+		maxPages      int
+		concurrency   int
+		want          []string
+		wantTruncated bool
+	}{
+		{name: "all pages", concurrency: 8, want: []string{"b1", "d1", "a1", "a2", "a3"}},
+		{name: "sequential", concurrency: 1, want: []string{"b1", "d1", "a1", "a2", "a3"}},
+		{name: "first page only", maxPages: 1, concurrency: 4, want: []string{"b1", "d1", "a1", "a2"}, wantTruncated: true},
+		{name: "max items", maxItems: 3, concurrency: 4, want: []string{"b1", "d1", "a1"}, wantTruncated: true},
+		{name: "max items not reached", maxItems: 5, concurrency: 4, want: []string{"b1", "d1", "a1", "a2", "a3"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			instances, truncated, err := listInstancesInCompartments(client, core.ListInstancesRequest{}, compartmentIDs, names, test.maxItems, test.maxPages, test.concurrency)
+			if err != nil {
+				t.Fatalf("listInstancesInCompartments() error = %v", err)
+			}
+			var got []string
+			for _, instance := range instances {
+				got = append(got, stringValue(instance.DisplayName))
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("listInstancesInCompartments() = %v, want %v", got, test.want)
+			}
+			if truncated != test.wantTruncated {
+				t.Errorf("truncated = %v, want %v", truncated, test.wantTruncated)
+			}
+		})
+	}
+
+	_, _, err := listInstancesInCompartments(&fakeCompute{err: errors.New("boom")}, core.ListInstancesRequest{}, compartmentIDs, names, 0, 0, 4)
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("listInstancesInCompartments() error = %v, want it to contain %q", err, "boom")
+	}
+}
+
+func TestCachedImageNames(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	const compartmentID = "ocid1.compartment.oc1..images"
+	client := &fakeCompute{images: map[string][]core.Image{
+		compartmentID: {testImage("ocid1.image.oc1..a", "alpha"), testImage("ocid1.image.oc1..b", "beta")},
+	}}
+
+	for i := 0; i < 2; i++ {
+		names, err := cachedImageNames(client, compartmentID)
+		if err != nil {
+			t.Fatalf("cachedImageNames() error = %v", err)
+		}
+		if want := []string{"alpha", "beta"}; !reflect.DeepEqual(names, want) {
+			t.Fatalf("cachedImageNames() = %v, want %v", names, want)
+		}
+	}
+	if len(client.imageQueries) != 1 {
+		t.Errorf("ListImages called %d times, want 1 (second call served from cache)", len(client.imageQueries))
+	}
+
+	// The compartment cache TTL does not affect the image name cache
+	defer func(ttl time.Duration) { compartmentCacheTTL = ttl }(compartmentCacheTTL)
+	compartmentCacheTTL = 0
+	if _, err := cachedImageNames(client, compartmentID); err != nil {
+		t.Fatalf("cachedImageNames() error = %v", err)
+	}
+	if len(client.imageQueries) != 1 {
+		t.Errorf("ListImages called %d times, want 1 (--cache-ttl 0 keeps the image name cache)", len(client.imageQueries))
+	}
+
+	defer func() { cacheDisabled = false }()
+	cacheDisabled = true
+	if _, err := cachedImageNames(client, compartmentID); err != nil {
+		t.Fatalf("cachedImageNames() error = %v", err)
+	}
+	if len(client.imageQueries) != 2 {
+		t.Errorf("ListImages called %d times, want 2 (--no-cache bypasses the cache)", len(client.imageQueries))
+	}
+}
+
+func TestNewInstanceWithVnicsJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		vnics         []vnicJSON
+		wantPrivateIP string
+		wantPublicIP  string
+	}{
+		{name: "no vnics"},
+		{name: "primary vnic", vnics: []vnicJSON{{PrivateIP: "10.0.1.5"}, {PrivateIP: "10.0.0.5", PublicIP: "203.0.113.5", Primary: true}}, wantPrivateIP: "10.0.0.5", wantPublicIP: "203.0.113.5"},
+		{name: "no primary flag", vnics: []vnicJSON{{PrivateIP: "10.0.1.5"}, {PrivateIP: "10.0.0.5"}}, wantPrivateIP: "10.0.1.5"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := newInstanceWithVnicsJSON(instanceJSON{ID: "ocid1.instance.oc1..a"}, test.vnics)
+			if got.PrivateIP != test.wantPrivateIP || got.PublicIP != test.wantPublicIP {
+				t.Errorf("newInstanceWithVnicsJSON() IPs = %q/%q, want %q/%q", got.PrivateIP, got.PublicIP, test.wantPrivateIP, test.wantPublicIP)
+			}
+		})
+	}
+}
+
+func TestTeardownInstance(t *testing.T) {
+	const instanceID = "ocid1.instance.oc1..a"
+	tests := []struct {
+		name               string
+		preserveBootVolume bool
+	}{
+		{name: "image launch deletes the boot volume"},
+		{name: "--boot-volume-id launch preserves it", preserveBootVolume: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &fakeCompute{}
+			if err := teardownInstance(client, instanceID, test.preserveBootVolume); err != nil {
+				t.Fatalf("teardownInstance() error = %v", err)
+			}
+			if len(client.terminations) != 1 {
+				t.Fatalf("TerminateInstance called %d times, want 1", len(client.terminations))
+			}
+			request := client.terminations[0]
+			if stringValue(request.InstanceId) != instanceID {
+				t.Errorf("TerminateInstance instance = %q, want %q", stringValue(request.InstanceId), instanceID)
+			}
+			if request.PreserveBootVolume == nil || *request.PreserveBootVolume != test.preserveBootVolume {
+				t.Errorf("TerminateInstance PreserveBootVolume = %v, want %v", request.PreserveBootVolume, test.preserveBootVolume)
+			}
+		})
+	}
+}
+
+func TestGroupInstancesByCompartment(t *testing.T) {
+	instance := func(id, compartmentID string) core.Instance {
+		return core.Instance{Id: common.String(id), CompartmentId: common.String(compartmentID)}
+	}
+	instances := []core.Instance{
+		instance("a", "ocid1.compartment.oc1..prod"),
+		instance("b", "ocid1.compartment.oc1..unknown"),
+		instance("c", "ocid1.compartment.oc1..prod"),
+	}
+	paths := map[string]string{"ocid1.compartment.oc1..prod": "root/team/prod"}
+
+	keys, groups := groupInstances(instances, "compartment", paths)
+	wantKeys := []string{"ocid1.compartment.oc1..unknown", "root/team/prod"}
+	if !reflect.DeepEqual(keys, wantKeys) {
+		t.Errorf("groupInstances() keys = %v, want %v", keys, wantKeys)
+	}
+	if want := []int{0, 2}; !reflect.DeepEqual(groups["root/team/prod"], want) {
+		t.Errorf("groupInstances() root/team/prod = %v, want %v", groups["root/team/prod"], want)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// waitForSSH waits until a TCP connection to port 22 of host succeeds, timeout elapses, or the
+// command is interrupted.
+func waitForSSH(host string, timeout time.Duration) error {
+	address := net.JoinHostPort(host, "22")
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.DialTimeout("tcp", address, 5*time.Second)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("SSH on %s not reachable after %s: %w", address, timeout, err)
+		}
+		pause(5 * time.Second)
+		if err := rootContext.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// runOnReadyCommand runs a local shell command with the instance's ID and IPs exported as
+// OCI_INSTANCE_ID, OCI_INSTANCE_PUBLIC_IP and OCI_INSTANCE_PRIVATE_IP, streaming its output.
+// It returns the command's exit code.
+func runOnReadyCommand(command, instanceID, publicIP, privateIP string) (int, error) {
+	c := exec.Command("sh", "-c", command)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(),
+		"OCI_INSTANCE_ID="+instanceID,
+		"OCI_INSTANCE_PUBLIC_IP="+publicIP,
+		"OCI_INSTANCE_PRIVATE_IP="+privateIP,
+	)
+
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 1, fmt.Errorf("failed to run command: %w", err)
+	}
+	return 0, nil
+}
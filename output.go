@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormat returns the validated value of the persistent --output flag.
+func outputFormat(cmd *cobra.Command) (string, error) {
+	output, _ := cmd.Flags().GetString("output")
+	switch output {
+	case "", "text":
+		return "text", nil
+	case "json", "json-meta", "yaml", "csv":
+		return output, nil
+	default:
+		return "", fmt.Errorf("invalid --output '%s' (must be 'text', 'json', 'json-meta', 'yaml' or 'csv')", output)
+	}
+}
+
+// isStructuredOutput reports whether an output format is machine-readable (JSON, YAML or CSV).
+func isStructuredOutput(output string) bool {
+	return output == "json" || output == "json-meta" || output == "yaml" || output == "csv"
+}
+
+// listMeta describes a list result for --output json-meta.
+type listMeta struct {
+	Count     int    `json:"count"`
+	Truncated bool   `json:"truncated"`
+	Region    string `json:"region,omitempty"`
+}
+
+// listEnvelope wraps list items together with their metadata for --output json-meta.
+type listEnvelope struct {
+	Items interface{} `json:"items"`
+	Meta  listMeta    `json:"meta"`
+}
+
+// printList prints list items as a bare JSON array for --output json (a YAML sequence for
+// --output yaml, CSV rows for --output csv), or wrapped in a {"items": ..., "meta": ...} envelope for --output json-meta.
+func printList(output string, items interface{}, meta listMeta) error {
+	if output == "json-meta" {
+		return printJSON(listEnvelope{Items: items, Meta: meta})
+	}
+	return printStructured(output, items)
+}
+
+// printStructured writes v to stdout as YAML for --output yaml, CSV for --output csv and as
+// JSON otherwise.
+func printStructured(output string, v interface{}) error {
+	switch output {
+	case "yaml":
+		return printYAML(v)
+	case "csv":
+		return printCSV(v)
+	}
+	return printJSON(v)
+}
+
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// printYAML writes v to stdout as YAML. The value is converted through its JSON encoding so
+// YAML output uses the same field names and ordering as JSON output.
+func printYAML(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	clearYAMLStyle(&node)
+
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// printCSV writes v to stdout as CSV: a list becomes one row per item and a single object
+// becomes one row. Like YAML output, the value goes through its JSON encoding, so the header
+// uses the JSON field names in order. Nested objects and arrays are written as compact JSON.
+func printCSV(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return err
+	}
+	if len(document.Content) == 0 {
+		return nil
+	}
+	root := document.Content[0]
+	records := []*yaml.Node{root}
+	if root.Kind == yaml.SequenceNode {
+		records = root.Content
+	}
+
+	// Collect the columns of all records, as optional fields may be missing from some
+	var columns []string
+	seen := map[string]bool{}
+	for _, record := range records {
+		if record.Kind != yaml.MappingNode {
+			return errors.New("CSV output requires a list of objects")
+		}
+		for i := 0; i < len(record.Content); i += 2 {
+			if key := record.Content[i].Value; !seen[key] {
+				seen[key] = true
+				columns = append(columns, key)
+			}
+		}
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	writer := csv.NewWriter(os.Stdout)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	for _, record := range records {
+		cells := map[string]string{}
+		for i := 0; i < len(record.Content); i += 2 {
+			cell, err := csvCell(record.Content[i+1])
+			if err != nil {
+				return err
+			}
+			cells[record.Content[i].Value] = cell
+		}
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = cells[column]
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvCell renders one field for CSV output: scalars as their text, null as empty and nested
+// values as compact JSON.
+func csvCell(node *yaml.Node) (string, error) {
+	if node.Kind == yaml.ScalarNode {
+		if node.Tag == "!!null" {
+			return "", nil
+		}
+		return node.Value, nil
+	}
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// clearYAMLStyle resets the flow/quoting style inherited from the JSON source to block style.
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
+
+// stringValue dereferences an optional SDK string, returning "" for nil.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// float32Value dereferences an optional SDK float32, returning 0 for nil.
+func float32Value(f *float32) float32 {
+	if f == nil {
+		return 0
+	}
+	return *f
+}
+
+// progress prints a progress message in text mode only, so it never corrupts JSON output.
+func progress(output, format string, args ...interface{}) {
+	if output == "text" {
+		fmt.Printf(format+"\n", args...)
+	}
+}
+
+// confirmPrompt asks the user to type "yes" to proceed and reports whether they did.
+func confirmPrompt(message string) bool {
+	fmt.Printf("%s Type 'yes' to continue: ", message)
+	reader := bufio.NewReader(os.Stdin)
+	answer, _ := reader.ReadString('\n')
+	return strings.TrimSpace(answer) == "yes"
+}
+
+// serviceErrorMessage returns the error text with an OCI service error in its chain shortened to
+// "Code: message" instead of the SDK's multi-line error dump, and the plain error text for
+// anything else.
+func serviceErrorMessage(err error) string {
+	var serviceErr common.ServiceError
+	if errors.As(err, &serviceErr) {
+		short := fmt.Sprintf("%s: %s", serviceErr.GetCode(), serviceErr.GetMessage())
+		if serviceErrText, ok := serviceErr.(error); ok {
+			return strings.Replace(err.Error(), serviceErrText.Error(), short, 1)
+		}
+		return short
+	}
+	return err.Error()
+}
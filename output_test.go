@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeServiceError is a common.ServiceError whose Error() is a multi-line dump like the SDK's.
+type fakeServiceError struct{}
+
+func (fakeServiceError) Error() string {
+	return "Error returned by Compute Service. Http Status Code: 404.\nError Code: NotAuthorizedOrNotFound.\nMessage: Authorization failed"
+}
+func (fakeServiceError) GetHTTPStatusCode() int  { return 404 }
+func (fakeServiceError) GetMessage() string      { return "Authorization failed" }
+func (fakeServiceError) GetCode() string         { return "NotAuthorizedOrNotFound" }
+func (fakeServiceError) GetOpcRequestID() string { return "" }
+
+func TestServiceErrorMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "plain error", err: errors.New("boom"), want: "boom"},
+		{name: "service error", err: fakeServiceError{}, want: "NotAuthorizedOrNotFound: Authorization failed"},
+		{name: "wrapped service error", err: fmt.Errorf("getting instance: %w", fakeServiceError{}), want: "getting instance: NotAuthorizedOrNotFound: Authorization failed"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := serviceErrorMessage(test.err); got != test.want {
+				t.Errorf("serviceErrorMessage() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// pageSize is the number of items requested per API call by paginated list commands.
+const pageSize = 100
+
+// addMaxItemsFlag registers --max-items, plus --limit as its deprecated alias, on a list command.
+func addMaxItemsFlag(cmd *cobra.Command) {
+	cmd.Flags().Int("max-items", 0, "(Optional) Maximum total number of results to return across all pages (0 means all)")
+	cmd.Flags().Int("limit", 0, "Deprecated alias of --max-items")
+	_ = cmd.Flags().MarkDeprecated("limit", "use --max-items instead")
+}
+
+// maxItems returns the total result cap requested with --max-items (or --limit); 0 means no cap.
+func maxItems(cmd *cobra.Command) int {
+	if cmd.Flags().Changed("max-items") {
+		value, _ := cmd.Flags().GetInt("max-items")
+		return value
+	}
+	value, _ := cmd.Flags().GetInt("limit")
+	return value
+}
+
+// collectPages calls fetch with successive page tokens until there are no more pages or
+// maxItems items (0 means unlimited) have been collected. It reports whether the result was
+// truncated by maxItems.
+func collectPages[T any](maxItems int, fetch func(page *string) ([]T, *string, error)) ([]T, bool, error) {
+	return collectPagesUpTo(maxItems, 0, fetch)
+}
+
+// collectPagesUpTo is collectPages with an additional cap on the number of pages fetched
+// (0 means unlimited). Stopping early because of maxPages also counts as truncated.
+func collectPagesUpTo[T any](maxItems, maxPages int, fetch func(page *string) ([]T, *string, error)) ([]T, bool, error) {
+	var items []T
+	var page *string
+	for pages := 1; ; pages++ {
+		pageItems, nextPage, err := fetch(page)
+		if err != nil {
+			return nil, false, err
+		}
+		items = append(items, pageItems...)
+
+		if maxItems > 0 && len(items) >= maxItems {
+			truncated := len(items) > maxItems || nextPage != nil
+			return items[:maxItems], truncated, nil
+		}
+		if nextPage == nil {
+			return items, false, nil
+		}
+		if maxPages > 0 && pages >= maxPages {
+			return items, true, nil
+		}
+		page = nextPage
+	}
+}
+
+// printListFooter prints the number of items listed in text mode, noting when --max-items cut
+// the list short.
+func printListFooter(count int, truncated bool, noun string) {
+	if truncated {
+		fmt.Printf("\n%d %s shown (more available; raise --max-items or set it to 0 for all)\n", count, noun)
+		return
+	}
+	fmt.Printf("\n%d %s\n", count, noun)
+}
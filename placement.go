@@ -0,0 +1,252 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/spf13/cobra"
+)
+
+// placementPlan returns the availability domains to try, in order: startAD repeated
+// startAttempts times, followed by every other AD once when tryAll is set.
+func placementPlan(startAD string, startAttempts int, tryAll bool, allADs []string) []string {
+	if startAttempts < 1 {
+		startAttempts = 1
+	}
+	var plan []string
+	for i := 0; i < startAttempts; i++ {
+		plan = append(plan, startAD)
+	}
+	if tryAll {
+		for _, ad := range allADs {
+			if ad != startAD {
+				plan = append(plan, ad)
+			}
+		}
+	}
+	return plan
+}
+
+// isOutOfCapacityError reports whether a launch failed because the AD has no host capacity.
+func isOutOfCapacityError(err error) bool {
+	var serviceErr common.ServiceError
+	if !errors.As(err, &serviceErr) {
+		return false
+	}
+	return strings.Contains(strings.ToLower(serviceErr.GetMessage()), "out of host capacity") ||
+		strings.Contains(strings.ToLower(serviceErr.GetMessage()), "out of capacity")
+}
+
+// launchWithPlacement launches the instance in the first AD of plan that has capacity,
+// retrying only on out-of-capacity errors and printing every placement decision.
+// Each attempt gets its own retry token derived from the request's token.
+func launchWithPlacement(client core.ComputeClient, request core.LaunchInstanceRequest, plan []string, retryInterval time.Duration) (core.LaunchInstanceResponse, error) {
+	baseToken := stringValue(request.OpcRetryToken)
+	var lastErr error
+	for i, ad := range plan {
+		if i > 0 {
+			if plan[i-1] == ad {
+				fmt.Printf("Placement: retrying %s in %s (attempt %d of %d)...\n", ad, retryInterval, i+1, len(plan))
+				pause(retryInterval)
+			} else {
+				fmt.Printf("Placement: falling back to %s (attempt %d of %d)...\n", ad, i+1, len(plan))
+			}
+		} else if len(plan) > 1 {
+			fmt.Printf("Placement: trying %s (attempt 1 of %d)...\n", ad, len(plan))
+		}
+
+		adName := ad
+		request.AvailabilityDomain = &adName
+		if baseToken != "" {
+			request.OpcRetryToken = common.String(fmt.Sprintf("%s-%d", baseToken, i))
+		}
+		response, err := apiCall(client.LaunchInstance, request)
+		if err == nil {
+			if len(plan) > 1 {
+				fmt.Printf("Placement: launched in %s.\n", ad)
+			}
+			return response, nil
+		}
+		if !isOutOfCapacityError(err) {
+			return response, err
+		}
+		fmt.Printf("Placement: %s is out of capacity.\n", ad)
+		lastErr = err
+	}
+	return core.LaunchInstanceResponse{}, fmt.Errorf("no capacity in any of the tried availability domains: %w", lastErr)
+}
+
+// listAvailabilityDomains returns the availability domains visible to a compartment.
+func listAvailabilityDomains(configProvider common.ConfigurationProvider, compartmentID string) ([]identity.AvailabilityDomain, error) {
+	identityClient, err := identity.NewIdentityClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create identity client: %w", err)
+	}
+	response, err := apiCall(identityClient.ListAvailabilityDomains, identity.ListAvailabilityDomainsRequest{
+		CompartmentId: &compartmentID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list availability domains: %w", err)
+	}
+	return response.Items, nil
+}
+
+// listAvailabilityDomainNames returns the names of the availability domains visible to a compartment.
+func listAvailabilityDomainNames(configProvider common.ConfigurationProvider, compartmentID string) ([]string, error) {
+	ads, err := listAvailabilityDomains(configProvider, compartmentID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(ads))
+	for _, ad := range ads {
+		names = append(names, *ad.Name)
+	}
+	return names, nil
+}
+
+// availabilityDomainJSON is the serializable projection of an availability domain.
+type availabilityDomainJSON struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+}
+
+func newListADsCmd() *cobra.Command {
+	var listADsCmd = &cobra.Command{
+		Use:   "list-ads",
+		Short: "List the availability domains of a compartment",
+		Long: `Lists the availability domains visible to a compartment. AD names carry a
+tenancy-specific prefix (e.g. 'Uocm:US-ASHBURN-AD-1'); use them as-is with
+'instances create --availability-domain' or '--preferred-ad'.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			compartmentInput, _ := cmd.Flags().GetString("compartment-id")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			var compartmentID string
+			if compartmentInput == "" {
+				compartmentID, err = configProvider.TenancyOCID()
+				if err != nil {
+					return fmt.Errorf("getting tenancy OCID: %w", err)
+				}
+			} else {
+				compartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
+				if err != nil {
+					return fmt.Errorf("resolving compartment: %w", err)
+				}
+			}
+
+			ads, err := listAvailabilityDomains(configProvider, compartmentID)
+			if err != nil {
+				return err
+			}
+
+			if isStructuredOutput(output) {
+				items := make([]availabilityDomainJSON, len(ads))
+				for i, ad := range ads {
+					items[i] = availabilityDomainJSON{Name: stringValue(ad.Name), ID: stringValue(ad.Id)}
+				}
+				region, _ := configProvider.Region()
+				if err := printList(output, items, listMeta{Count: len(items), Region: region}); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			for _, ad := range ads {
+				fmt.Println(stringValue(ad.Name))
+			}
+			return nil
+		},
+	}
+
+	listADsCmd.Flags().String("compartment-id", "", "(Optional) OCID, name or path of the compartment (defaults to tenancy root)")
+
+	return listADsCmd
+}
+
+func newListFaultDomainsCmd() *cobra.Command {
+	var listFaultDomainsCmd = &cobra.Command{
+		Use:   "list-fault-domains",
+		Short: "List the fault domains of an availability domain",
+		Long: `Lists the fault domains of an availability domain (e.g. 'FAULT-DOMAIN-1'); use the
+names with 'instances create --fault-domain'.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			adFlag, _ := cmd.Flags().GetString("availability-domain")
+			compartmentInput, _ := cmd.Flags().GetString("compartment-id")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			var compartmentID string
+			if compartmentInput == "" {
+				compartmentID, err = configProvider.TenancyOCID()
+				if err != nil {
+					return fmt.Errorf("getting tenancy OCID: %w", err)
+				}
+			} else {
+				compartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
+				if err != nil {
+					return fmt.Errorf("resolving compartment: %w", err)
+				}
+			}
+
+			identityClient, err := identity.NewIdentityClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating identity client: %w", err)
+			}
+			response, err := apiCall(identityClient.ListFaultDomains, identity.ListFaultDomainsRequest{
+				CompartmentId:      &compartmentID,
+				AvailabilityDomain: &adFlag,
+			})
+			if err != nil {
+				return fmt.Errorf("listing fault domains: %w", err)
+			}
+
+			if isStructuredOutput(output) {
+				items := make([]faultDomainJSON, len(response.Items))
+				for i, fd := range response.Items {
+					items[i] = faultDomainJSON{Name: stringValue(fd.Name), ID: stringValue(fd.Id), AvailabilityDomain: stringValue(fd.AvailabilityDomain)}
+				}
+				region, _ := configProvider.Region()
+				if err := printList(output, items, listMeta{Count: len(items), Region: region}); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			for _, fd := range response.Items {
+				fmt.Println(stringValue(fd.Name))
+			}
+			return nil
+		},
+	}
+
+	listFaultDomainsCmd.Flags().String("availability-domain", "", "Name of the availability domain (Required)")
+	listFaultDomainsCmd.Flags().String("compartment-id", "", "(Optional) OCID, name or path of the compartment (defaults to tenancy root)")
+	_ = listFaultDomainsCmd.MarkFlagRequired("availability-domain")
+
+	return listFaultDomainsCmd
+}
+
+// faultDomainJSON is the serializable projection of a fault domain.
+type faultDomainJSON struct {
+	Name               string `json:"name"`
+	ID                 string `json:"id"`
+	AvailabilityDomain string `json:"availabilityDomain"`
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// addRetryTokenFlag registers --retry-token on a command that creates resources.
+func addRetryTokenFlag(cmd *cobra.Command) {
+	cmd.Flags().String("retry-token", "", "(Optional) Idempotency token for the create request; re-running with the same token never creates a duplicate (generated if empty)")
+}
+
+// retryToken returns the --retry-token value, or a newly generated UUID for this logical
+// operation. The token is printed to stderr, so it never mixes into structured output on stdout,
+// and an interrupted run can be retried with it.
+func retryToken(cmd *cobra.Command) string {
+	token, _ := cmd.Flags().GetString("retry-token")
+	if token == "" {
+		token = newUUID()
+	}
+	fmt.Fprintf(os.Stderr, "Retry token: %s (re-run with --retry-token %s to retry safely)\n", token, token)
+	return token
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to read random bytes: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
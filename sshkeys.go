@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// collectPublicKeys gathers the SSH public keys given as a comma-separated list and in key files
+// (one key per line, blank lines and # comments ignored) and checks that each one parses as an
+// authorized_keys entry.
+func collectPublicKeys(inline string, files []string) ([]string, error) {
+	var keys []string
+	for _, key := range strings.Split(inline, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if err := validatePublicKey(key); err != nil {
+			return nil, fmt.Errorf("invalid public SSH key '%s': %w", abbreviateKey(key), err)
+		}
+		keys = append(keys, key)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(expandHome(file))
+		if err != nil {
+			return nil, fmt.Errorf("reading public key file '%s': %w", file, err)
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+		for lineNumber := 1; scanner.Scan(); lineNumber++ {
+			key := strings.TrimSpace(scanner.Text())
+			if key == "" || strings.HasPrefix(key, "#") {
+				continue
+			}
+			if err := validatePublicKey(key); err != nil {
+				return nil, fmt.Errorf("invalid public SSH key in '%s' line %d ('%s'): %w", file, lineNumber, abbreviateKey(key), err)
+			}
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public SSH keys provided (use --public-keys, --public-key-file or --default-ssh-key)")
+	}
+	return keys, nil
+}
+
+// validatePublicKey reports whether key is a single well-formed authorized_keys entry.
+func validatePublicKey(key string) error {
+	_, _, _, rest, err := ssh.ParseAuthorizedKey([]byte(key))
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return fmt.Errorf("unexpected data after the key")
+	}
+	return nil
+}
+
+// abbreviateKey shortens a key for error messages, keeping its type and the start of its data.
+func abbreviateKey(key string) string {
+	const width = 40
+	if len(key) <= width {
+		return key
+	}
+	return key[:width] + "..."
+}
+
+// defaultPublicKeyFiles are the public keys --default-ssh-key loads, in order, when present.
+var defaultPublicKeyFiles = []string{"~/.ssh/id_ed25519.pub", "~/.ssh/id_rsa.pub"}
+
+// findDefaultPublicKeyFiles returns the default public key files that exist.
+func findDefaultPublicKeyFiles() ([]string, error) {
+	var files []string
+	for _, file := range defaultPublicKeyFiles {
+		if _, err := os.Stat(expandHome(file)); err == nil {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("--default-ssh-key: none of %s exists", strings.Join(defaultPublicKeyFiles, ", "))
+	}
+	return files, nil
+}
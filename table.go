@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+// ocidDisplayWidth is the width OCID columns are shortened to unless --no-truncate is given.
+const ocidDisplayWidth = 32
+
+// tableColumn describes one column of a text table.
+type tableColumn struct {
+	header  string
+	numeric bool // right-aligned
+	ocid    bool // shortened to ocidDisplayWidth unless --no-truncate
+}
+
+// textTable renders rows as aligned columns with text/tabwriter for --output text.
+type textTable struct {
+	columns  []tableColumn
+	rows     [][]string
+	truncate bool
+}
+
+// addNoTruncateFlag registers --no-truncate on a command that prints a table.
+func addNoTruncateFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("no-truncate", false, "Show full OCIDs in table output instead of shortening them")
+}
+
+// newTextTable returns an empty table with the given columns, honouring the command's --no-truncate flag.
+func newTextTable(cmd *cobra.Command, columns ...tableColumn) *textTable {
+	noTruncateFlag, _ := cmd.Flags().GetBool("no-truncate")
+	return &textTable{columns: columns, truncate: !noTruncateFlag}
+}
+
+// addRow appends a row; it must have one cell per column.
+func (t *textTable) addRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// print writes the header and rows to stdout.
+func (t *textTable) print() {
+	lines := make([][]string, 0, len(t.rows)+1)
+	header := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		header[i] = column.header
+	}
+	lines = append(lines, header)
+	for _, row := range t.rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			if t.columns[i].ocid && t.truncate {
+				cell = truncateOCID(cell, ocidDisplayWidth)
+			}
+			cells[i] = cell
+		}
+		lines = append(lines, cells)
+	}
+
+	// tabwriter only aligns left, so numeric columns are padded to a common width up front
+	for i, column := range t.columns {
+		if !column.numeric {
+			continue
+		}
+		width := 0
+		for _, line := range lines {
+			width = max(width, utf8.RuneCountInString(line[i]))
+		}
+		for _, line := range lines {
+			line[i] = strings.Repeat(" ", width-utf8.RuneCountInString(line[i])) + line[i]
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, line := range lines {
+		fmt.Fprintln(w, strings.Join(line, "\t"))
+	}
+	w.Flush()
+}
+
+// truncateOCID shortens an OCID to width characters, keeping its resource type prefix and its
+// distinctive tail, e.g. "ocid1.instance.oc1....x7kq2vbnfa".
+func truncateOCID(ocid string, width int) string {
+	const tail = 10
+	if len(ocid) <= width || width <= tail+3 {
+		return ocid
+	}
+	return ocid[:width-tail-3] + "..." + ocid[len(ocid)-tail:]
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// parseFreeformTags parses repeated "key=value" flag values into freeform tags.
+func parseFreeformTags(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	tags := map[string]string{}
+	for _, value := range values {
+		key, tagValue, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid freeform tag '%s' (expected key=value)", value)
+		}
+		tags[strings.TrimSpace(key)] = tagValue
+	}
+	return tags, nil
+}
+
+// parseDefinedTags parses repeated "namespace.key=value" flag values into defined tags.
+func parseDefinedTags(values []string) (map[string]map[string]interface{}, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	tags := map[string]map[string]interface{}{}
+	for _, value := range values {
+		name, tagValue, ok := strings.Cut(value, "=")
+		namespace, key, hasNamespace := strings.Cut(strings.TrimSpace(name), ".")
+		if !ok || !hasNamespace || namespace == "" || key == "" {
+			return nil, fmt.Errorf("invalid defined tag '%s' (expected namespace.key=value)", value)
+		}
+		if tags[namespace] == nil {
+			tags[namespace] = map[string]interface{}{}
+		}
+		tags[namespace][key] = tagValue
+	}
+	return tags, nil
+}
+
+// filterInstancesByFreeformTags returns the instances that carry every one of the given freeform tags.
+func filterInstancesByFreeformTags(instances []core.Instance, tags map[string]string) []core.Instance {
+	var matched []core.Instance
+	for _, instance := range instances {
+		matches := true
+		for key, value := range tags {
+			if actual, ok := instance.FreeformTags[key]; !ok || actual != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			matched = append(matched, instance)
+		}
+	}
+	return matched
+}
+
+// formatTags renders freeform and defined tags as sorted "key=value" and "namespace.key=value" pairs.
+func formatTags(freeform map[string]string, defined map[string]map[string]interface{}) []string {
+	var pairs []string
+	for key, value := range freeform {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, value))
+	}
+	for namespace, keys := range defined {
+		for key, value := range keys {
+			pairs = append(pairs, fmt.Sprintf("%s.%s=%v", namespace, key, value))
+		}
+	}
+	sort.Strings(pairs)
+	return pairs
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/limits"
+)
+
+// launchRequirements describes the resources a launch would consume.
+type launchRequirements struct {
+	CompartmentID         string
+	AvailabilityDomain    string
+	Shape                 string
+	ImageID               string
+	Ocpus                 float32
+	MemoryInGBs           float32
+	CapacityReservationID string
+}
+
+// validateLaunch checks a planned launch against the compute service limits (and quotas) of the
+// target compartment and, if given, the capacity reservation. Each check is printed as it runs.
+// It returns false if any check determines that the launch would fail.
+func validateLaunch(configProvider common.ConfigurationProvider, computeClient core.ComputeClient, tenancyOCID string, req launchRequirements) (bool, error) {
+	ok := true
+
+	// Determine how many OCPUs and how much memory the shape will consume
+	shape, err := findShape(computeClient, req.CompartmentID, req.ImageID, req.Shape)
+	if err != nil {
+		return false, err
+	}
+	ocpus, memory := req.Ocpus, req.MemoryInGBs
+	if ocpus == 0 && shape.Ocpus != nil {
+		ocpus = *shape.Ocpus
+	}
+	if memory == 0 {
+		if shape.MemoryOptions != nil && shape.MemoryOptions.DefaultPerOcpuInGBs != nil && req.Ocpus != 0 {
+			memory = ocpus * *shape.MemoryOptions.DefaultPerOcpuInGBs
+		} else if shape.MemoryInGBs != nil {
+			memory = *shape.MemoryInGBs
+		}
+	}
+	fmt.Printf("Requested resources: %.1f OCPUs, %.1f GB memory\n", ocpus, memory)
+
+	// Check service limits for the shape family
+	limitsClient, err := limits.NewLimitsClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return false, fmt.Errorf("failed to create limits client: %w", err)
+	}
+
+	prefix := shapeLimitPrefix(req.Shape)
+	required := map[string]int64{
+		prefix + "-core-count":   int64(math.Ceil(float64(ocpus))),
+		prefix + "-memory-count": int64(math.Ceil(float64(memory))),
+		prefix + "-count":        1,
+	}
+	checked := 0
+	for _, limitName := range []string{prefix + "-core-count", prefix + "-memory-count", prefix + "-count"} {
+		definition, err := findLimitDefinition(limitsClient, tenancyOCID, "compute", limitName)
+		if err != nil {
+			return false, err
+		}
+		if definition == nil {
+			continue
+		}
+		checked++
+
+		request := limits.GetResourceAvailabilityRequest{
+			ServiceName:   common.String("compute"),
+			LimitName:     &limitName,
+			CompartmentId: &req.CompartmentID,
+		}
+		if definition.ScopeType == limits.LimitDefinitionSummaryScopeTypeAd {
+			request.AvailabilityDomain = &req.AvailabilityDomain
+		}
+		response, err := apiCall(limitsClient.GetResourceAvailability, request)
+		if err != nil {
+			return false, fmt.Errorf("failed to get availability of limit '%s': %w", limitName, err)
+		}
+
+		available := int64(0)
+		if response.Available != nil {
+			available = *response.Available
+		}
+		if available < required[limitName] {
+			fmt.Printf("  FAIL  %s: need %d, available %d\n", limitName, required[limitName], available)
+			ok = false
+		} else {
+			fmt.Printf("  OK    %s: need %d, available %d\n", limitName, required[limitName], available)
+		}
+	}
+	if checked == 0 {
+		fmt.Printf("  SKIP  no compute limit found for shape family '%s'\n", prefix)
+	}
+
+	// Check the capacity reservation, if any
+	if req.CapacityReservationID != "" {
+		reservationOK, err := checkCapacityReservation(computeClient, req)
+		if err != nil {
+			return false, err
+		}
+		ok = ok && reservationOK
+	}
+
+	return ok, nil
+}
+
+// findShape returns the named shape from the shapes compatible with an image in a compartment.
+func findShape(client computeAPI, compartmentID, imageID, shapeName string) (core.Shape, error) {
+	request := core.ListShapesRequest{
+		CompartmentId: &compartmentID,
+	}
+	if imageID != "" {
+		request.ImageId = &imageID
+	}
+	for {
+		response, err := listShapes(client, request)
+		if err != nil {
+			return core.Shape{}, fmt.Errorf("failed to list shapes: %w", err)
+		}
+		for _, shape := range response.Items {
+			if shape.Shape != nil && *shape.Shape == shapeName {
+				return shape, nil
+			}
+		}
+		if response.OpcNextPage == nil {
+			break
+		}
+		request.Page = response.OpcNextPage
+	}
+	return core.Shape{}, fmt.Errorf("no shape found with name '%s' compatible with image '%s' in compartment '%s'", shapeName, imageID, compartmentID)
+}
+
+// shapeLimitPrefix derives the compute limit name prefix of a shape's family, e.g.
+// "VM.Standard.A1.Flex" -> "standard-a1" and "VM.Standard2.4" -> "standard2".
+func shapeLimitPrefix(shape string) string {
+	segments := strings.Split(shape, ".")
+	if len(segments) > 1 {
+		segments = segments[1:] // drop VM/BM
+	}
+	var parts []string
+	for _, segment := range segments {
+		if segment == "Flex" || strings.IndexFunc(segment, func(r rune) bool { return !unicode.IsDigit(r) }) == -1 {
+			continue
+		}
+		parts = append(parts, strings.ToLower(segment))
+	}
+	return strings.Join(parts, "-")
+}
+
+// findLimitDefinition returns the definition of a service limit, or nil if it does not exist.
+func findLimitDefinition(client limits.LimitsClient, tenancyOCID, serviceName, limitName string) (*limits.LimitDefinitionSummary, error) {
+	response, err := apiCall(client.ListLimitDefinitions, limits.ListLimitDefinitionsRequest{
+		CompartmentId: &tenancyOCID,
+		ServiceName:   &serviceName,
+		Name:          &limitName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list limit definitions for '%s': %w", limitName, err)
+	}
+	for _, definition := range response.Items {
+		if definition.Name != nil && *definition.Name == limitName {
+			return &definition, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkCapacityReservation verifies that a capacity reservation is usable for the planned launch.
+func checkCapacityReservation(client core.ComputeClient, req launchRequirements) (bool, error) {
+	response, err := apiCall(client.GetComputeCapacityReservation, core.GetComputeCapacityReservationRequest{
+		CapacityReservationId: &req.CapacityReservationID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to get capacity reservation '%s': %w", req.CapacityReservationID, err)
+	}
+	reservation := response.ComputeCapacityReservation
+
+	if reservation.LifecycleState != core.ComputeCapacityReservationLifecycleStateActive {
+		fmt.Printf("  FAIL  capacity reservation is %s, not ACTIVE\n", reservation.LifecycleState)
+		return false, nil
+	}
+	if stringValue(reservation.AvailabilityDomain) != req.AvailabilityDomain {
+		fmt.Printf("  FAIL  capacity reservation is in %s, not %s\n", stringValue(reservation.AvailabilityDomain), req.AvailabilityDomain)
+		return false, nil
+	}
+	for _, config := range reservation.InstanceReservationConfigs {
+		if config.InstanceShape == nil || *config.InstanceShape != req.Shape {
+			continue
+		}
+		free := *config.ReservedCount - *config.UsedCount
+		if free > 0 {
+			fmt.Printf("  OK    capacity reservation: %d of %d %s slots free\n", free, *config.ReservedCount, req.Shape)
+			return true, nil
+		}
+	}
+	fmt.Printf("  FAIL  capacity reservation has no free slot for shape %s\n", req.Shape)
+	return false, nil
+}
+
+// resolveBaselineUtilization validates a burstable baseline against the baselines the shape
+// supports and returns it as a launch shape config value.
+func resolveBaselineUtilization(client core.ComputeClient, compartmentID, imageID, shapeName, baseline string) (core.LaunchInstanceShapeConfigDetailsBaselineOcpuUtilizationEnum, error) {
+	value, ok := core.GetMappingLaunchInstanceShapeConfigDetailsBaselineOcpuUtilizationEnum(baseline)
+	if !ok {
+		return "", fmt.Errorf("invalid --baseline-utilization '%s' (must be one of %s)", baseline, strings.Join(core.GetLaunchInstanceShapeConfigDetailsBaselineOcpuUtilizationEnumStringValues(), ", "))
+	}
+
+	shape, err := findShape(client, compartmentID, imageID, shapeName)
+	if err != nil {
+		return "", err
+	}
+	if len(shape.BaselineOcpuUtilizations) == 0 {
+		return "", fmt.Errorf("shape '%s' is not burstable and does not support --baseline-utilization", shapeName)
+	}
+	var supported []string
+	for _, utilization := range shape.BaselineOcpuUtilizations {
+		if string(utilization) == string(value) {
+			return value, nil
+		}
+		supported = append(supported, string(utilization))
+	}
+	return "", fmt.Errorf("shape '%s' does not support baseline '%s' (supported: %s)", shapeName, baseline, strings.Join(supported, ", "))
+}
@@ -0,0 +1,781 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/spf13/cobra"
+)
+
+func newVolumesCmd() *cobra.Command {
+	var volumesCmd = &cobra.Command{
+		Use:   "volumes",
+		Short: "Manage block volumes",
+	}
+
+	var attachCmd = &cobra.Command{
+		Use:   "attach",
+		Short: "Attach a block volume to a compute instance",
+		Long: `Attaches a block volume to a compute instance.
+
+For iSCSI attachments the iscsiadm commands needed to connect the volume are printed.
+With --emit-script a ready-to-run shell script is written that connects the volume and,
+optionally, formats it (--format) and mounts it (--mount-point). Nothing is executed remotely.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// 1. Get Flags
+			instanceIDFlag, _ := cmd.Flags().GetString("instance-id")
+			volumeIDFlag, _ := cmd.Flags().GetString("volume-id")
+			typeFlag, _ := cmd.Flags().GetString("type")
+			emitScriptFlag, _ := cmd.Flags().GetString("emit-script")
+			formatFlag, _ := cmd.Flags().GetBool("format")
+			mountPointFlag, _ := cmd.Flags().GetString("mount-point")
+			waitFlag, _ := cmd.Flags().GetBool("wait")
+			waitTimeoutFlag, _ := cmd.Flags().GetInt("wait-timeout")
+
+			if typeFlag != "iscsi" && typeFlag != "paravirtualized" {
+				return fmt.Errorf("invalid --type '%s' (must be 'iscsi' or 'paravirtualized')", typeFlag)
+			}
+			if emitScriptFlag != "" && typeFlag != "iscsi" {
+				return errors.New("--emit-script is only supported for iscsi attachments")
+			}
+			if (formatFlag || mountPointFlag != "") && emitScriptFlag == "" {
+				return errors.New("--format and --mount-point require --emit-script")
+			}
+			if mountPointFlag != "" && !path.IsAbs(mountPointFlag) {
+				return fmt.Errorf("--mount-point must be an absolute path, got '%s'", mountPointFlag)
+			}
+
+			// 2. Setup Config Provider
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			// 3. Create Compute Client
+			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating compute client: %w", err)
+			}
+
+			// 4. Build Attach Details
+			var attachDetails core.AttachVolumeDetails
+			if typeFlag == "iscsi" {
+				attachDetails = core.AttachIScsiVolumeDetails{
+					InstanceId: &instanceIDFlag,
+					VolumeId:   &volumeIDFlag,
+				}
+			} else {
+				attachDetails = core.AttachParavirtualizedVolumeDetails{
+					InstanceId: &instanceIDFlag,
+					VolumeId:   &volumeIDFlag,
+				}
+			}
+
+			fmt.Println("Attaching volume...")
+
+			// 5. Call API
+			response, err := apiCall(computeClient.AttachVolume, core.AttachVolumeRequest{
+				AttachVolumeDetails: attachDetails,
+				OpcRetryToken:       common.String(retryToken(cmd)),
+			})
+			if err != nil {
+				return fmt.Errorf("attaching volume: %w", err)
+			}
+
+			// 6. Print Result
+			attachment := response.VolumeAttachment
+			fmt.Printf("Volume attachment initiated successfully.\nAttachment ID: %s\nState: %s\n", *attachment.GetId(), attachment.GetLifecycleState())
+
+			// 7. Wait for the attachment if requested
+			if waitFlag {
+				fmt.Println("Waiting for attachment to reach ATTACHED...")
+				attachment, err = waitForVolumeAttachmentState(computeClient, *attachment.GetId(), core.VolumeAttachmentLifecycleStateAttached, time.Duration(waitTimeoutFlag)*time.Second)
+				if err != nil {
+					return fmt.Errorf("volume was not attached: %w", err)
+				}
+				fmt.Printf("Attachment is %s.\n", attachment.GetLifecycleState())
+			}
+
+			iscsiAttachment, ok := attachment.(core.IScsiVolumeAttachment)
+			if !ok {
+				return nil
+			}
+
+			// 8. Print iSCSI Connect Commands
+			commands, err := iscsiConnectCommands(iscsiAttachment)
+			if err != nil {
+				return fmt.Errorf("cannot print the iSCSI connect commands: %w (use --wait so the attachment is ATTACHED first)", err)
+			}
+			target, _ := iscsiTarget(iscsiAttachment)
+			fmt.Printf("\nIQN: %s\nTarget: %s\n", stringValue(iscsiAttachment.Iqn), target)
+			fmt.Println("\nRun the following commands on the instance to connect the volume:")
+			for _, command := range commands {
+				fmt.Printf("  %s\n", command)
+			}
+
+			// 9. Emit Script if requested
+			if emitScriptFlag != "" {
+				script, err := iscsiAttachScript(iscsiAttachment, formatFlag, mountPointFlag)
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(emitScriptFlag, []byte(script), 0755); err != nil {
+					return fmt.Errorf("writing script '%s': %w", emitScriptFlag, err)
+				}
+				fmt.Printf("\nWrote attach script to %s (copy it to the instance and run it there).\n", emitScriptFlag)
+			}
+			return nil
+		},
+	}
+
+	attachCmd.Flags().String("instance-id", "", "OCID of the instance to attach the volume to (Required)")
+	attachCmd.Flags().String("volume-id", "", "OCID of the block volume to attach (Required)")
+	attachCmd.Flags().String("type", "iscsi", "Attachment type: 'iscsi' or 'paravirtualized'")
+	attachCmd.Flags().String("emit-script", "", "(Optional, iscsi only) Write a shell script that connects, formats and mounts the volume to this path")
+	attachCmd.Flags().Bool("format", false, "(Optional) Make the emitted script create an ext4 filesystem if the volume has none")
+	attachCmd.Flags().String("mount-point", "", "(Optional) Make the emitted script mount the volume at this path")
+	attachCmd.Flags().Bool("wait", false, "(Optional) Wait until the attachment is ATTACHED before exiting")
+	attachCmd.Flags().Int("wait-timeout", 300, "(Optional) Maximum number of seconds to wait with --wait")
+	addRetryTokenFlag(attachCmd)
+	_ = attachCmd.MarkFlagRequired("instance-id")
+	_ = attachCmd.MarkFlagRequired("volume-id")
+
+	var listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the block volumes of a compartment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			compartmentInput, _ := cmd.Flags().GetString("compartment-id")
+			adFlag, _ := cmd.Flags().GetString("availability-domain")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			var compartmentID string
+			if compartmentInput == "" {
+				compartmentID, err = configProvider.TenancyOCID()
+				if err != nil {
+					return fmt.Errorf("getting tenancy OCID: %w", err)
+				}
+			} else {
+				compartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
+				if err != nil {
+					return fmt.Errorf("resolving compartment: %w", err)
+				}
+			}
+
+			blockstorageClient, err := core.NewBlockstorageClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating block storage client: %w", err)
+			}
+
+			request := core.ListVolumesRequest{
+				CompartmentId: &compartmentID,
+				Limit:         common.Int(pageSize),
+			}
+			if adFlag != "" {
+				request.AvailabilityDomain = &adFlag
+			}
+			volumes, truncated, err := collectPages(maxItems(cmd), func(page *string) ([]core.Volume, *string, error) {
+				request.Page = page
+				response, err := apiCall(blockstorageClient.ListVolumes, request)
+				return response.Items, response.OpcNextPage, err
+			})
+			if err != nil {
+				return fmt.Errorf("listing volumes: %w", err)
+			}
+
+			if isStructuredOutput(output) {
+				items := make([]volumeJSON, len(volumes))
+				for i, volume := range volumes {
+					items[i] = newVolumeJSON(volume)
+				}
+				region, _ := configProvider.Region()
+				if err := printList(output, items, listMeta{Count: len(items), Truncated: truncated, Region: region}); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			if len(volumes) == 0 {
+				fmt.Println("No volumes found.")
+				return nil
+			}
+			table := newTextTable(cmd,
+				tableColumn{header: "DISPLAY NAME"},
+				tableColumn{header: "ID", ocid: true},
+				tableColumn{header: "SIZE (GB)", numeric: true},
+				tableColumn{header: "VPUS/GB", numeric: true},
+				tableColumn{header: "STATE"},
+			)
+			for _, volume := range volumes {
+				details := newVolumeJSON(volume)
+				table.addRow(details.DisplayName, details.ID, fmt.Sprint(details.SizeInGBs), fmt.Sprint(details.VpusPerGB), details.LifecycleState)
+			}
+			table.print()
+			return nil
+		},
+	}
+
+	listCmd.Flags().String("compartment-id", "", "(Optional) OCID, name or path of the compartment (defaults to tenancy root)")
+	listCmd.Flags().String("availability-domain", "", "(Optional) Only list volumes in this Availability Domain")
+	addMaxItemsFlag(listCmd)
+	addNoTruncateFlag(listCmd)
+
+	var createCmd = &cobra.Command{
+		Use:   "create",
+		Short: "Create a block volume",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			compartmentInput, _ := cmd.Flags().GetString("compartment-id")
+			adFlag, _ := cmd.Flags().GetString("availability-domain")
+			sizeInGBsFlag, _ := cmd.Flags().GetInt64("size-in-gbs")
+			nameFlag, _ := cmd.Flags().GetString("name")
+			vpusPerGBFlag, _ := cmd.Flags().GetInt64("vpus-per-gb")
+			waitFlag, _ := cmd.Flags().GetBool("wait")
+			waitTimeoutFlag, _ := cmd.Flags().GetInt("wait-timeout")
+
+			if sizeInGBsFlag <= 0 {
+				return fmt.Errorf("invalid --size-in-gbs %d", sizeInGBsFlag)
+			}
+			if vpusPerGBFlag < 0 {
+				return fmt.Errorf("invalid --vpus-per-gb %d", vpusPerGBFlag)
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			var compartmentID string
+			if compartmentInput == "" {
+				compartmentID, err = configProvider.TenancyOCID()
+				if err != nil {
+					return fmt.Errorf("getting tenancy OCID: %w", err)
+				}
+			} else {
+				compartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
+				if err != nil {
+					return fmt.Errorf("resolving compartment: %w", err)
+				}
+			}
+
+			displayName := nameFlag
+			if displayName == "" {
+				displayName = fmt.Sprintf("volume-%s", time.Now().Format("20060102-1504"))
+			}
+
+			blockstorageClient, err := core.NewBlockstorageClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating block storage client: %w", err)
+			}
+
+			response, err := apiCall(blockstorageClient.CreateVolume, core.CreateVolumeRequest{
+				CreateVolumeDetails: core.CreateVolumeDetails{
+					CompartmentId:      &compartmentID,
+					AvailabilityDomain: &adFlag,
+					DisplayName:        &displayName,
+					SizeInGBs:          common.Int64(sizeInGBsFlag),
+					VpusPerGB:          common.Int64(vpusPerGBFlag),
+				},
+				OpcRetryToken: common.String(retryToken(cmd)),
+			})
+			if err != nil {
+				return fmt.Errorf("creating volume: %w", err)
+			}
+			volumeID := stringValue(response.Volume.Id)
+			fmt.Printf("Volume creation initiated.\nVolume ID: %s\nDisplay Name: %s\nState: %s\n", volumeID, displayName, response.Volume.LifecycleState)
+
+			if !waitFlag {
+				return nil
+			}
+			fmt.Println("Waiting for volume to become AVAILABLE...")
+			volume, err := waitForVolumeAvailable(blockstorageClient, volumeID, time.Duration(waitTimeoutFlag)*time.Second)
+			if err != nil {
+				return fmt.Errorf("volume did not become AVAILABLE: %w", err)
+			}
+			fmt.Printf("Volume is %s.\n", volume.LifecycleState)
+			return nil
+		},
+	}
+
+	createCmd.Flags().String("compartment-id", "", "(Optional) OCID, name or path of the compartment to create the volume in (defaults to tenancy root)")
+	createCmd.Flags().String("availability-domain", "", "Availability Domain of the volume; it can only be attached to instances in the same AD (Required)")
+	createCmd.Flags().Int64("size-in-gbs", 0, "Size of the volume in GB (Required)")
+	createCmd.Flags().String("name", "", "(Optional) Display name for the new volume (auto-generated if empty)")
+	createCmd.Flags().Int64("vpus-per-gb", 10, "(Optional) Performance in VPUs/GB: 0 (lower cost), 10 (balanced), 20 (higher performance) or 30-120 (ultra high)")
+	createCmd.Flags().Bool("wait", false, "(Optional) Wait until the volume is AVAILABLE before exiting")
+	createCmd.Flags().Int("wait-timeout", 600, "(Optional) Maximum number of seconds to wait with --wait")
+	addRetryTokenFlag(createCmd)
+	_ = createCmd.MarkFlagRequired("availability-domain")
+	_ = createCmd.MarkFlagRequired("size-in-gbs")
+
+	var detachCmd = &cobra.Command{
+		Use:   "detach",
+		Short: "Detach a block volume from a compute instance",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			attachmentIDFlag, _ := cmd.Flags().GetString("attachment-id")
+			waitFlag, _ := cmd.Flags().GetBool("wait")
+			waitTimeoutFlag, _ := cmd.Flags().GetInt("wait-timeout")
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating compute client: %w", err)
+			}
+
+			_, err = apiCall(computeClient.DetachVolume, core.DetachVolumeRequest{VolumeAttachmentId: &attachmentIDFlag})
+			if err != nil {
+				return fmt.Errorf("detaching volume: %w", err)
+			}
+			fmt.Printf("Detach initiated for attachment %s.\n", attachmentIDFlag)
+
+			if !waitFlag {
+				return nil
+			}
+			fmt.Println("Waiting for attachment to reach DETACHED...")
+			attachment, err := waitForVolumeAttachmentState(computeClient, attachmentIDFlag, core.VolumeAttachmentLifecycleStateDetached, time.Duration(waitTimeoutFlag)*time.Second)
+			if err != nil {
+				return fmt.Errorf("volume was not detached: %w", err)
+			}
+			fmt.Printf("Attachment is %s.\n", attachment.GetLifecycleState())
+			return nil
+		},
+	}
+
+	detachCmd.Flags().String("attachment-id", "", "OCID of the volume attachment to remove (Required)")
+	detachCmd.Flags().Bool("wait", false, "(Optional) Wait until the attachment is DETACHED before exiting")
+	detachCmd.Flags().Int("wait-timeout", 300, "(Optional) Maximum number of seconds to wait with --wait")
+	_ = detachCmd.MarkFlagRequired("attachment-id")
+
+	var listBootCmd = &cobra.Command{
+		Use:   "list-boot",
+		Short: "List the boot volumes of a compartment",
+		Long: `Lists boot volumes, including those preserved after terminating an instance with
+--preserve-boot-volume. Without --availability-domain every Availability Domain is listed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			compartmentInput, _ := cmd.Flags().GetString("compartment-id")
+			adFlag, _ := cmd.Flags().GetString("availability-domain")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			tenancyOCID, err := configProvider.TenancyOCID()
+			if err != nil {
+				return fmt.Errorf("getting tenancy OCID: %w", err)
+			}
+			compartmentID := tenancyOCID
+			if compartmentInput != "" {
+				compartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
+				if err != nil {
+					return fmt.Errorf("resolving compartment: %w", err)
+				}
+			}
+
+			// ListBootVolumes is scoped to one Availability Domain
+			ads := []string{adFlag}
+			if adFlag == "" {
+				ads, err = listAvailabilityDomainNames(configProvider, tenancyOCID)
+				if err != nil {
+					return err
+				}
+			}
+
+			blockstorageClient, err := core.NewBlockstorageClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating block storage client: %w", err)
+			}
+
+			// --max-items caps the combined result; once it is reached the remaining
+			// Availability Domains are not listed and the result counts as truncated
+			limit := maxItems(cmd)
+			var bootVolumes []core.BootVolume
+			truncated := false
+			for _, ad := range ads {
+				remaining := 0
+				if limit > 0 {
+					remaining = limit - len(bootVolumes)
+					if remaining <= 0 {
+						truncated = true
+						break
+					}
+				}
+				request := core.ListBootVolumesRequest{
+					CompartmentId:      &compartmentID,
+					AvailabilityDomain: common.String(ad),
+					Limit:              common.Int(pageSize),
+				}
+				items, more, err := collectPages(remaining, func(page *string) ([]core.BootVolume, *string, error) {
+					request.Page = page
+					response, err := apiCall(blockstorageClient.ListBootVolumes, request)
+					return response.Items, response.OpcNextPage, err
+				})
+				if err != nil {
+					return fmt.Errorf("listing boot volumes in %s: %w", ad, err)
+				}
+				bootVolumes = append(bootVolumes, items...)
+				truncated = truncated || more
+			}
+
+			if isStructuredOutput(output) {
+				items := make([]bootVolumeJSON, len(bootVolumes))
+				for i, bootVolume := range bootVolumes {
+					items[i] = newBootVolumeJSON(bootVolume)
+				}
+				region, _ := configProvider.Region()
+				if err := printList(output, items, listMeta{Count: len(items), Truncated: truncated, Region: region}); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			if len(bootVolumes) == 0 {
+				fmt.Println("No boot volumes found.")
+				return nil
+			}
+			table := newTextTable(cmd,
+				tableColumn{header: "DISPLAY NAME"},
+				tableColumn{header: "ID", ocid: true},
+				tableColumn{header: "AVAILABILITY DOMAIN"},
+				tableColumn{header: "SIZE (GB)", numeric: true},
+				tableColumn{header: "STATE"},
+			)
+			for _, bootVolume := range bootVolumes {
+				details := newBootVolumeJSON(bootVolume)
+				table.addRow(details.DisplayName, details.ID, details.AvailabilityDomain, fmt.Sprint(details.SizeInGBs), details.LifecycleState)
+			}
+			table.print()
+			return nil
+		},
+	}
+
+	listBootCmd.Flags().String("compartment-id", "", "(Optional) OCID, name or path of the compartment (defaults to tenancy root)")
+	listBootCmd.Flags().String("availability-domain", "", "(Optional) Only list boot volumes in this Availability Domain")
+	addMaxItemsFlag(listBootCmd)
+	addNoTruncateFlag(listBootCmd)
+
+	var bootInfoCmd = &cobra.Command{
+		Use:   "boot-info",
+		Short: "Show information about a boot volume",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			idFlag, _ := cmd.Flags().GetString("id")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			blockstorageClient, err := core.NewBlockstorageClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating block storage client: %w", err)
+			}
+
+			response, err := apiCall(blockstorageClient.GetBootVolume, core.GetBootVolumeRequest{BootVolumeId: &idFlag})
+			if err != nil {
+				return fmt.Errorf("getting boot volume: %w", err)
+			}
+			details := newBootVolumeJSON(response.BootVolume)
+
+			if isStructuredOutput(output) {
+				if err := printStructured(output, details); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			fmt.Println("Boot Volume Details:")
+			fmt.Printf("  ID: %s\n", details.ID)
+			fmt.Printf("  Display Name: %s\n", details.DisplayName)
+			fmt.Printf("  State: %s\n", details.LifecycleState)
+			fmt.Printf("  Size (GB): %d\n", details.SizeInGBs)
+			fmt.Printf("  VPUs/GB: %d\n", details.VpusPerGB)
+			fmt.Printf("  Source: %s\n", details.Source)
+			fmt.Printf("  Availability Domain: %s\n", details.AvailabilityDomain)
+			fmt.Printf("  Compartment ID: %s\n", details.CompartmentID)
+			fmt.Printf("  Time Created: %s\n", details.TimeCreated)
+			return nil
+		},
+	}
+
+	bootInfoCmd.Flags().String("id", "", "OCID of the boot volume (Required)")
+	_ = bootInfoCmd.MarkFlagRequired("id")
+
+	volumesCmd.AddCommand(attachCmd, detachCmd, listCmd, createCmd, listBootCmd, bootInfoCmd)
+
+	return volumesCmd
+}
+
+// volumeJSON is the projection of a block volume used for structured output.
+type volumeJSON struct {
+	ID                 string `json:"id"`
+	DisplayName        string `json:"displayName"`
+	AvailabilityDomain string `json:"availabilityDomain"`
+	SizeInGBs          int64  `json:"sizeInGBs"`
+	VpusPerGB          int64  `json:"vpusPerGB"`
+	LifecycleState     string `json:"lifecycleState"`
+}
+
+func newVolumeJSON(volume core.Volume) volumeJSON {
+	result := volumeJSON{
+		ID:                 stringValue(volume.Id),
+		DisplayName:        stringValue(volume.DisplayName),
+		AvailabilityDomain: stringValue(volume.AvailabilityDomain),
+		LifecycleState:     string(volume.LifecycleState),
+	}
+	if volume.SizeInGBs != nil {
+		result.SizeInGBs = *volume.SizeInGBs
+	}
+	if volume.VpusPerGB != nil {
+		result.VpusPerGB = *volume.VpusPerGB
+	}
+	return result
+}
+
+// bootVolumeJSON is the projection of a boot volume used for structured output.
+type bootVolumeJSON struct {
+	ID                 string `json:"id"`
+	DisplayName        string `json:"displayName"`
+	AvailabilityDomain string `json:"availabilityDomain"`
+	CompartmentID      string `json:"compartmentId"`
+	SizeInGBs          int64  `json:"sizeInGBs"`
+	VpusPerGB          int64  `json:"vpusPerGB,omitempty"`
+	ImageID            string `json:"imageId,omitempty"`
+	Source             string `json:"source"`
+	LifecycleState     string `json:"lifecycleState"`
+	TimeCreated        string `json:"timeCreated,omitempty"`
+}
+
+func newBootVolumeJSON(bootVolume core.BootVolume) bootVolumeJSON {
+	result := bootVolumeJSON{
+		ID:                 stringValue(bootVolume.Id),
+		DisplayName:        stringValue(bootVolume.DisplayName),
+		AvailabilityDomain: stringValue(bootVolume.AvailabilityDomain),
+		CompartmentID:      stringValue(bootVolume.CompartmentId),
+		ImageID:            stringValue(bootVolume.ImageId),
+		Source:             bootVolumeSource(bootVolume),
+		LifecycleState:     string(bootVolume.LifecycleState),
+	}
+	if bootVolume.SizeInGBs != nil {
+		result.SizeInGBs = *bootVolume.SizeInGBs
+	} else if bootVolume.SizeInMBs != nil {
+		result.SizeInGBs = *bootVolume.SizeInMBs / 1024
+	}
+	if bootVolume.VpusPerGB != nil {
+		result.VpusPerGB = *bootVolume.VpusPerGB
+	}
+	if bootVolume.TimeCreated != nil {
+		result.TimeCreated = bootVolume.TimeCreated.Format(time.RFC3339)
+	}
+	return result
+}
+
+// bootVolumeSource describes what a boot volume was created from.
+func bootVolumeSource(bootVolume core.BootVolume) string {
+	switch source := bootVolume.SourceDetails.(type) {
+	case core.BootVolumeSourceFromBootVolumeDetails:
+		return "boot volume " + stringValue(source.Id)
+	case core.BootVolumeSourceFromBootVolumeBackupDetails:
+		return "boot volume backup " + stringValue(source.Id)
+	case core.BootVolumeSourceFromBootVolumeReplicaDetails:
+		return "boot volume replica " + stringValue(source.Id)
+	}
+	if bootVolume.ImageId != nil {
+		return "image " + *bootVolume.ImageId
+	}
+	return "unknown"
+}
+
+// waitForVolumeAvailable polls a volume until it is AVAILABLE, becomes FAULTY or TERMINATED,
+// or timeout elapses.
+func waitForVolumeAvailable(client core.BlockstorageClient, volumeID string, timeout time.Duration) (core.Volume, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		response, err := apiCall(client.GetVolume, core.GetVolumeRequest{VolumeId: &volumeID})
+		if err != nil {
+			return core.Volume{}, fmt.Errorf("failed to get volume: %w", err)
+		}
+		volume := response.Volume
+		switch volume.LifecycleState {
+		case core.VolumeLifecycleStateAvailable:
+			return volume, nil
+		case core.VolumeLifecycleStateFaulty, core.VolumeLifecycleStateTerminating, core.VolumeLifecycleStateTerminated:
+			return volume, fmt.Errorf("volume entered state %s", volume.LifecycleState)
+		}
+		if time.Now().After(deadline) {
+			return volume, fmt.Errorf("timed out after %s (last state: %s)", timeout, volume.LifecycleState)
+		}
+		pause(5 * time.Second)
+	}
+}
+
+// waitForVolumeAttachmentState polls a volume attachment until it reaches target or timeout
+// elapses. An attachment that ends up DETACHED while waiting for ATTACHED fails immediately.
+func waitForVolumeAttachmentState(client core.ComputeClient, attachmentID string, target core.VolumeAttachmentLifecycleStateEnum, timeout time.Duration) (core.VolumeAttachment, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		response, err := apiCall(client.GetVolumeAttachment, core.GetVolumeAttachmentRequest{VolumeAttachmentId: &attachmentID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get volume attachment: %w", err)
+		}
+		attachment := response.VolumeAttachment
+		state := attachment.GetLifecycleState()
+		if state == target {
+			return attachment, nil
+		}
+		if target == core.VolumeAttachmentLifecycleStateAttached && state == core.VolumeAttachmentLifecycleStateDetached {
+			return attachment, fmt.Errorf("attachment entered state %s", state)
+		}
+		if time.Now().After(deadline) {
+			return attachment, fmt.Errorf("timed out after %s (last state: %s)", timeout, state)
+		}
+		pause(5 * time.Second)
+	}
+}
+
+// iscsiDeviceWaitSeconds bounds how long the emitted script waits for the device to appear after
+// the iSCSI login.
+const iscsiDeviceWaitSeconds = 60
+
+// iscsiTarget returns the "ip:port" portal address of an iSCSI attachment. An attachment that is
+// still ATTACHING may not have one yet.
+func iscsiTarget(attachment core.IScsiVolumeAttachment) (string, error) {
+	if attachment.Ipv4 == nil || attachment.Port == nil {
+		return "", fmt.Errorf("iSCSI attachment %s has no target address yet (state %s)", stringValue(attachment.Id), attachment.LifecycleState)
+	}
+	return fmt.Sprintf("%s:%d", *attachment.Ipv4, *attachment.Port), nil
+}
+
+// iscsiIqn returns the IQN of an iSCSI attachment, which may also be missing while ATTACHING.
+func iscsiIqn(attachment core.IScsiVolumeAttachment) (string, error) {
+	if attachment.Iqn == nil {
+		return "", fmt.Errorf("iSCSI attachment %s has no IQN yet (state %s)", stringValue(attachment.Id), attachment.LifecycleState)
+	}
+	return *attachment.Iqn, nil
+}
+
+// iscsiDevicePath returns the stable by-path device name the volume shows up as once connected.
+func iscsiDevicePath(attachment core.IScsiVolumeAttachment) (string, error) {
+	target, err := iscsiTarget(attachment)
+	if err != nil {
+		return "", err
+	}
+	iqn, err := iscsiIqn(attachment)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("/dev/disk/by-path/ip-%s-iscsi-%s-lun-1", target, iqn), nil
+}
+
+// iscsiConnectCommands builds the iscsiadm commands that register, enable and log in to the attachment's target.
+func iscsiConnectCommands(attachment core.IScsiVolumeAttachment) ([]string, error) {
+	target, err := iscsiTarget(attachment)
+	if err != nil {
+		return nil, err
+	}
+	iqn, err := iscsiIqn(attachment)
+	if err != nil {
+		return nil, err
+	}
+	commands := []string{
+		fmt.Sprintf("sudo iscsiadm -m node -o new -T %s -p %s", iqn, target),
+		fmt.Sprintf("sudo iscsiadm -m node -o update -T %s -n node.startup -v automatic", iqn),
+	}
+	if attachment.ChapUsername != nil && attachment.ChapSecret != nil {
+		commands = append(commands,
+			fmt.Sprintf("sudo iscsiadm -m node -T %s -p %s -o update -n node.session.auth.authmethod -v CHAP", iqn, target),
+			fmt.Sprintf("sudo iscsiadm -m node -T %s -p %s -o update -n node.session.auth.username -v %s", iqn, target, *attachment.ChapUsername),
+			fmt.Sprintf("sudo iscsiadm -m node -T %s -p %s -o update -n node.session.auth.password -v %s", iqn, target, *attachment.ChapSecret),
+		)
+	}
+	commands = append(commands, fmt.Sprintf("sudo iscsiadm -m node -T %s -p %s -l", iqn, target))
+	return commands, nil
+}
+
+// iscsiAttachScript renders a shell script that connects the attachment and optionally formats and mounts it.
+func iscsiAttachScript(attachment core.IScsiVolumeAttachment, format bool, mountPoint string) (string, error) {
+	device, err := iscsiDevicePath(attachment)
+	if err != nil {
+		return "", err
+	}
+	commands, err := iscsiConnectCommands(attachment)
+	if err != nil {
+		return "", err
+	}
+
+	var script strings.Builder
+	script.WriteString("#!/bin/sh\n")
+	script.WriteString(fmt.Sprintf("# Generated by oci-cli for volume attachment %s\n", stringValue(attachment.Id)))
+	script.WriteString("set -e\n\n")
+	for _, command := range commands {
+		script.WriteString(command + "\n")
+	}
+
+	script.WriteString(fmt.Sprintf("\nDEVICE=%s\n", device))
+	script.WriteString("# Give up if the device does not appear, e.g. because the login silently failed\n")
+	script.WriteString("WAITED=0\n")
+	script.WriteString("while [ ! -e \"$DEVICE\" ]; do\n")
+	script.WriteString(fmt.Sprintf("  if [ \"$WAITED\" -ge %d ]; then\n", iscsiDeviceWaitSeconds))
+	script.WriteString(fmt.Sprintf("    echo \"$DEVICE did not appear after %d seconds\" >&2\n", iscsiDeviceWaitSeconds))
+	script.WriteString("    exit 1\n")
+	script.WriteString("  fi\n")
+	script.WriteString("  sleep 1\n")
+	script.WriteString("  WAITED=$((WAITED + 1))\n")
+	script.WriteString("done\n")
+
+	if format {
+		script.WriteString("\n# Only create a filesystem if the device does not already have one\n")
+		script.WriteString("if ! sudo blkid \"$DEVICE\" >/dev/null 2>&1; then\n")
+		script.WriteString("  sudo mkfs.ext4 \"$DEVICE\"\n")
+		script.WriteString("fi\n")
+	}
+
+	if mountPoint != "" {
+		script.WriteString(fmt.Sprintf("\nMOUNT_POINT=%s\n", shellQuote(mountPoint)))
+		script.WriteString("sudo mkdir -p \"$MOUNT_POINT\"\n")
+		script.WriteString("sudo mount \"$DEVICE\" \"$MOUNT_POINT\"\n")
+	}
+
+	return script.String(), nil
+}
+
+// shellQuote quotes s as a single POSIX shell word, so it is used literally in generated scripts.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// getAvailableBootVolume returns a boot volume, failing unless it is AVAILABLE for use.
+func getAvailableBootVolume(configProvider common.ConfigurationProvider, bootVolumeID string) (core.BootVolume, error) {
+	blockstorageClient, err := core.NewBlockstorageClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return core.BootVolume{}, fmt.Errorf("creating block storage client: %w", err)
+	}
+	response, err := apiCall(blockstorageClient.GetBootVolume, core.GetBootVolumeRequest{BootVolumeId: &bootVolumeID})
+	if err != nil {
+		return core.BootVolume{}, fmt.Errorf("getting boot volume: %w", err)
+	}
+	if response.BootVolume.LifecycleState != core.BootVolumeLifecycleStateAvailable {
+		return core.BootVolume{}, fmt.Errorf("boot volume %s is %s, not AVAILABLE", bootVolumeID, response.BootVolume.LifecycleState)
+	}
+	return response.BootVolume, nil
+}
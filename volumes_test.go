@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func TestIscsiAttachScriptQuotesMountPoint(t *testing.T) {
+	attachment := core.IScsiVolumeAttachment{
+		Id:   common.String("ocid1.volumeattachment.oc1..a"),
+		Iqn:  common.String("iqn.2015-12.com.oracleiaas:abc"),
+		Ipv4: common.String("169.254.2.2"),
+		Port: common.Int(3260),
+	}
+	script, err := iscsiAttachScript(attachment, false, "/mnt/my data; rm -rf ~/it's")
+	if err != nil {
+		t.Fatalf("iscsiAttachScript() error = %v", err)
+	}
+
+	want := `MOUNT_POINT='/mnt/my data; rm -rf ~/it'\''s'` + "\n" +
+		`sudo mkdir -p "$MOUNT_POINT"` + "\n" +
+		`sudo mount "$DEVICE" "$MOUNT_POINT"` + "\n"
+	if !strings.HasSuffix(script, want) {
+		t.Errorf("iscsiAttachScript() ends with:\n%s\nwant:\n%s", script[strings.LastIndex(script, "\nMOUNT_POINT")+1:], want)
+	}
+}
+
+func TestIscsiAttachScriptRequiresTargetDetails(t *testing.T) {
+	attached := core.IScsiVolumeAttachment{
+		Id:   common.String("ocid1.volumeattachment.oc1..a"),
+		Iqn:  common.String("iqn.2015-12.com.oracleiaas:abc"),
+		Ipv4: common.String("169.254.2.2"),
+		Port: common.Int(3260),
+	}
+	withoutIqn, withoutIpv4, withoutPort := attached, attached, attached
+	withoutIqn.Iqn, withoutIpv4.Ipv4, withoutPort.Port = nil, nil, nil
+
+	for name, attachment := range map[string]core.IScsiVolumeAttachment{"iqn": withoutIqn, "ipv4": withoutIpv4, "port": withoutPort} {
+		if _, err := iscsiAttachScript(attachment, true, "/mnt/data"); err == nil {
+			t.Errorf("iscsiAttachScript() without %s: error = nil, want an error", name)
+		}
+		if _, err := iscsiConnectCommands(attachment); err == nil {
+			t.Errorf("iscsiConnectCommands() without %s: error = nil, want an error", name)
+		}
+	}
+
+	script, err := iscsiAttachScript(attached, false, "")
+	if err != nil {
+		t.Fatalf("iscsiAttachScript() error = %v", err)
+	}
+	if !strings.Contains(script, "exit 1") {
+		t.Errorf("iscsiAttachScript() waits for the device without a time limit:\n%s", script)
+	}
+}
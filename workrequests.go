@@ -0,0 +1,316 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/workrequests"
+	"github.com/spf13/cobra"
+)
+
+func newWorkRequestsCmd() *cobra.Command {
+	var workRequestsCmd = &cobra.Command{
+		Use:   "workrequests",
+		Short: "Inspect the work requests of asynchronous operations such as launch and terminate",
+	}
+
+	var getCmd = &cobra.Command{
+		Use:   "get",
+		Short: "Show the status of a work request",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			idFlag, _ := cmd.Flags().GetString("id")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := workrequests.NewWorkRequestClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating work request client: %w", err)
+			}
+
+			response, err := apiCall(client.GetWorkRequest, workrequests.GetWorkRequestRequest{WorkRequestId: &idFlag})
+			if err != nil {
+				return fmt.Errorf("getting work request: %w", err)
+			}
+			details := newWorkRequestJSON(response.WorkRequest)
+			if details.Status == string(workrequests.WorkRequestStatusFailed) {
+				details.Errors, err = listWorkRequestErrors(client, idFlag)
+				if err != nil {
+					return err
+				}
+			}
+
+			if isStructuredOutput(output) {
+				if err := printStructured(output, details); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			fmt.Printf("Work Request ID: %s\n", details.ID)
+			fmt.Printf("Operation: %s\n", details.OperationType)
+			fmt.Printf("Status: %s (%.0f%% complete)\n", details.Status, details.PercentComplete)
+			fmt.Printf("Accepted: %s\n", details.TimeAccepted)
+			if details.TimeFinished != "" {
+				fmt.Printf("Finished: %s\n", details.TimeFinished)
+			}
+			for _, resource := range details.Resources {
+				fmt.Printf("Resource: %s %s (%s)\n", resource.EntityType, resource.Identifier, resource.ActionType)
+			}
+			for _, message := range details.Errors {
+				fmt.Printf("Error: %s\n", message)
+			}
+			return nil
+		},
+	}
+
+	getCmd.Flags().String("id", "", "OCID of the work request (Required)")
+	_ = getCmd.MarkFlagRequired("id")
+
+	var listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the work requests of a compartment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			compartmentInput, _ := cmd.Flags().GetString("compartment-id")
+			resourceIDFlag, _ := cmd.Flags().GetString("resource-id")
+			output, err := outputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			configProvider, err := newConfigProvider(cmd)
+			if err != nil {
+				return err
+			}
+
+			var compartmentID string
+			if compartmentInput == "" {
+				compartmentID, err = configProvider.TenancyOCID()
+				if err != nil {
+					return fmt.Errorf("getting tenancy OCID: %w", err)
+				}
+			} else {
+				compartmentID, err = resolveCompartmentID(compartmentInput, configProvider)
+				if err != nil {
+					return fmt.Errorf("resolving compartment: %w", err)
+				}
+			}
+
+			client, err := workrequests.NewWorkRequestClientWithConfigurationProvider(configProvider)
+			if err != nil {
+				return fmt.Errorf("creating work request client: %w", err)
+			}
+
+			request := workrequests.ListWorkRequestsRequest{
+				CompartmentId: &compartmentID,
+				Limit:         common.Int(pageSize),
+			}
+			if resourceIDFlag != "" {
+				request.ResourceId = &resourceIDFlag
+			}
+			summaries, truncated, err := collectPages(maxItems(cmd), func(page *string) ([]workrequests.WorkRequestSummary, *string, error) {
+				request.Page = page
+				response, err := apiCall(client.ListWorkRequests, request)
+				return response.Items, response.OpcNextPage, err
+			})
+			if err != nil {
+				return fmt.Errorf("listing work requests: %w", err)
+			}
+
+			items := make([]workRequestJSON, len(summaries))
+			for i, summary := range summaries {
+				items[i] = newWorkRequestSummaryJSON(summary)
+			}
+			if isStructuredOutput(output) {
+				region, _ := configProvider.Region()
+				if err := printList(output, items, listMeta{Count: len(items), Truncated: truncated, Region: region}); err != nil {
+					return fmt.Errorf("encoding output: %w", err)
+				}
+				return nil
+			}
+			if len(items) == 0 {
+				fmt.Println("No work requests found.")
+				return nil
+			}
+			table := newTextTable(cmd,
+				tableColumn{header: "ID", ocid: true},
+				tableColumn{header: "OPERATION"},
+				tableColumn{header: "STATUS"},
+				tableColumn{header: "COMPLETE (%)", numeric: true},
+				tableColumn{header: "ACCEPTED"},
+			)
+			for _, item := range items {
+				table.addRow(item.ID, item.OperationType, item.Status, fmt.Sprintf("%.0f", item.PercentComplete), item.TimeAccepted)
+			}
+			table.print()
+			return nil
+		},
+	}
+
+	listCmd.Flags().String("compartment-id", "", "(Optional) OCID, name or path of the compartment (defaults to tenancy root)")
+	listCmd.Flags().String("resource-id", "", "(Optional) Only list work requests affecting this resource OCID")
+	addMaxItemsFlag(listCmd)
+	addNoTruncateFlag(listCmd)
+
+	workRequestsCmd.AddCommand(getCmd, listCmd)
+
+	return workRequestsCmd
+}
+
+// workRequestJSON is the serializable projection of a work request.
+type workRequestJSON struct {
+	ID              string                    `json:"id"`
+	OperationType   string                    `json:"operationType"`
+	Status          string                    `json:"status"`
+	PercentComplete float32                   `json:"percentComplete"`
+	TimeAccepted    string                    `json:"timeAccepted"`
+	TimeFinished    string                    `json:"timeFinished,omitempty"`
+	Resources       []workRequestResourceJSON `json:"resources,omitempty"`
+	Errors          []string                  `json:"errors,omitempty"`
+}
+
+// workRequestResourceJSON is the serializable projection of a resource affected by a work request.
+type workRequestResourceJSON struct {
+	EntityType string `json:"entityType"`
+	ActionType string `json:"actionType"`
+	Identifier string `json:"identifier"`
+}
+
+func newWorkRequestJSON(workRequest workrequests.WorkRequest) workRequestJSON {
+	result := workRequestJSON{
+		ID:              stringValue(workRequest.Id),
+		OperationType:   stringValue(workRequest.OperationType),
+		Status:          string(workRequest.Status),
+		PercentComplete: float32Value(workRequest.PercentComplete),
+		TimeAccepted:    formatSDKTime(workRequest.TimeAccepted),
+		TimeFinished:    formatSDKTime(workRequest.TimeFinished),
+	}
+	for _, resource := range workRequest.Resources {
+		result.Resources = append(result.Resources, workRequestResourceJSON{
+			EntityType: stringValue(resource.EntityType),
+			ActionType: string(resource.ActionType),
+			Identifier: stringValue(resource.Identifier),
+		})
+	}
+	return result
+}
+
+func newWorkRequestSummaryJSON(summary workrequests.WorkRequestSummary) workRequestJSON {
+	return workRequestJSON{
+		ID:              stringValue(summary.Id),
+		OperationType:   stringValue(summary.OperationType),
+		Status:          string(summary.Status),
+		PercentComplete: float32Value(summary.PercentComplete),
+		TimeAccepted:    formatSDKTime(summary.TimeAccepted),
+		TimeFinished:    formatSDKTime(summary.TimeFinished),
+	}
+}
+
+// formatSDKTime renders an optional SDK time as RFC 3339, or "" for nil.
+func formatSDKTime(t *common.SDKTime) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// latestWorkRequestForResource returns the ID of the most recently accepted work request affecting
+// a resource, or "" if there is none.
+func latestWorkRequestForResource(client workrequests.WorkRequestClient, compartmentID, resourceID string) (string, error) {
+	request := workrequests.ListWorkRequestsRequest{
+		CompartmentId: &compartmentID,
+		ResourceId:    &resourceID,
+	}
+	summaries, _, err := collectPages(0, func(page *string) ([]workrequests.WorkRequestSummary, *string, error) {
+		request.Page = page
+		response, err := apiCall(client.ListWorkRequests, request)
+		return response.Items, response.OpcNextPage, err
+	})
+	if err != nil {
+		return "", fmt.Errorf("listing work requests: %w", err)
+	}
+	var latest *workrequests.WorkRequestSummary
+	for i, summary := range summaries {
+		if summary.TimeAccepted == nil {
+			continue
+		}
+		if latest == nil || summary.TimeAccepted.After(latest.TimeAccepted.Time) {
+			latest = &summaries[i]
+		}
+	}
+	if latest == nil {
+		return "", nil
+	}
+	return stringValue(latest.Id), nil
+}
+
+// listWorkRequestErrors returns the "Code: message" lines of a work request's errors.
+func listWorkRequestErrors(client workrequests.WorkRequestClient, workRequestID string) ([]string, error) {
+	request := workrequests.ListWorkRequestErrorsRequest{WorkRequestId: &workRequestID}
+	items, _, err := collectPages(0, func(page *string) ([]workrequests.WorkRequestError, *string, error) {
+		request.Page = page
+		response, err := apiCall(client.ListWorkRequestErrors, request)
+		return response.Items, response.OpcNextPage, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing work request errors: %w", err)
+	}
+	messages := make([]string, len(items))
+	for i, item := range items {
+		messages[i] = fmt.Sprintf("%s: %s", stringValue(item.Code), stringValue(item.Message))
+	}
+	return messages, nil
+}
+
+// workRequestFailedError reports a work request that ended FAILED or CANCELED, with the messages
+// of its errors.
+type workRequestFailedError struct {
+	status   workrequests.WorkRequestStatusEnum
+	messages []string
+}
+
+func (e *workRequestFailedError) Error() string {
+	if len(e.messages) == 0 {
+		return fmt.Sprintf("work request %s", e.status)
+	}
+	return fmt.Sprintf("work request %s: %s", e.status, strings.Join(e.messages, "; "))
+}
+
+// waitForWorkRequest polls a work request until it succeeds, fails, or timeout elapses, printing
+// its status and percent complete whenever they change. A failed work request is reported as a
+// *workRequestFailedError.
+func waitForWorkRequest(client workrequests.WorkRequestClient, workRequestID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	lastProgress := ""
+	for {
+		response, err := apiCall(client.GetWorkRequest, workrequests.GetWorkRequestRequest{WorkRequestId: &workRequestID})
+		if err != nil {
+			return fmt.Errorf("failed to get work request: %w", err)
+		}
+		workRequest := response.WorkRequest
+		progress := fmt.Sprintf("%s (%.0f%% complete)", workRequest.Status, float32Value(workRequest.PercentComplete))
+		if progress != lastProgress {
+			fmt.Printf("Work request: %s\n", progress)
+			lastProgress = progress
+		}
+		switch workRequest.Status {
+		case workrequests.WorkRequestStatusSucceeded:
+			return nil
+		case workrequests.WorkRequestStatusFailed, workrequests.WorkRequestStatusCanceled:
+			// The error messages are best effort; the outcome is known either way
+			messages, _ := listWorkRequestErrors(client, workRequestID)
+			return &workRequestFailedError{status: workRequest.Status, messages: messages}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s (last status: %s)", timeout, workRequest.Status)
+		}
+		pause(5 * time.Second)
+	}
+}